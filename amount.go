@@ -1,23 +1,38 @@
 package trader
 
 import (
-	"fmt"
+	"errors"
 	"math"
 
 	"github.com/processout/decimal"
 )
 
-// Amount represents an amount in a given currency
+// Amount represents an amount in a given currency. The Trader of an Amount
+// is an immutable copy of the Trader it was created from, shared with the
+// other amounts created from it, and must not be modified
 type Amount struct {
-	Trader   Trader          `json:"-"`
+	Trader   *Trader         `json:"-"`
 	Value    decimal.Decimal `json:"value"`
 	Currency Currency        `json:"currency"`
+
+	// fixed is the fixed-point representation of Value, if known
+	fixed fixedPoint
 }
 
 // emptyAmount represents an empty amount
 var emptyAmount = Amount{}
 
-// NewAmount creates a new amount structure from a decimal and a currency
+// ErrTraderMismatch is the error returned when an operation is done on two
+// amounts that don't use the same Trader
+var ErrTraderMismatch = errors.New("The trader of a and b are not the same.")
+
+// ErrCurrencyMismatch is the error returned when an operation that never
+// converts is done on two amounts that are not in the same currency
+var ErrCurrencyMismatch = errors.New("The currency of a and b are not the same.")
+
+// NewAmount creates a new amount structure from a decimal and a currency.
+// The Amount references a copy of the rates of t, so modifying t afterwards
+// doesn't change it
 func (t Trader) NewAmount(d decimal.Decimal, code CurrencyCode) (Amount, error) {
 	c, err := t.Currencies.Find(code)
 	if err != nil {
@@ -25,21 +40,22 @@ func (t Trader) NewAmount(d decimal.Decimal, code CurrencyCode) (Amount, error)
 	}
 
 	return Amount{
-		Trader:   t,
+		Trader:   t.freeze(),
 		Value:    d,
 		Currency: c,
+		fixed:    newFixedPoint(d),
 	}, nil
 }
 
 // NewAmountFromFloat creates a new amount structure from a float and
 // a currency
-func (t *Trader) NewAmountFromFloat(f float64, c CurrencyCode) (Amount, error) {
+func (t Trader) NewAmountFromFloat(f float64, c CurrencyCode) (Amount, error) {
 	return t.NewAmount(decimal.NewFromFloat(f), c)
 }
 
 // NewAmountFromString creates a new amount structure from a string
 // and a currency. Returns an error if the string could not be parsed
-func (t *Trader) NewAmountFromString(s string, c CurrencyCode) (Amount, error) {
+func (t Trader) NewAmountFromString(s string, c CurrencyCode) (Amount, error) {
 	d, err := decimal.NewFromString(s)
 	if err != nil {
 		return emptyAmount, err
@@ -47,21 +63,52 @@ func (t *Trader) NewAmountFromString(s string, c CurrencyCode) (Amount, error) {
 	return t.NewAmount(d, c)
 }
 
+// trader returns the Trader of the Amount, or an empty Trader if it has none
+func (a Amount) trader() *Trader {
+	if a.Trader == nil {
+		return &Trader{}
+	}
+
+	return a.Trader
+}
+
+// sameTrader returns true if t1 and t2 are the same Trader, or have the
+// same rates. A nil Trader has the same rates as an empty Trader
+func sameTrader(t1, t2 *Trader) bool {
+	if t1 == t2 {
+		return true
+	}
+	if t1 == nil {
+		t1 = &emptyTrader
+	}
+	if t2 == nil {
+		t2 = &emptyTrader
+	}
+
+	return t1.Is(*t2)
+}
+
 // RateTo returns the rate that would be applied to convert the amount of a
-// to the target currency. An error is returned if the provided code is not
-// in the Amount Trader currency list
+// to the target currency. The values of both currencies are looked up in
+// the Trader of the Amount. An error is returned if the provided code, or
+// the currency of the Amount, is not in the Amount Trader currency list
 func (a Amount) RateTo(code CurrencyCode) (decimal.Decimal, error) {
-	c, err := a.Trader.Currencies.Find(code)
+	from, err := a.trader().Currencies.Find(a.Currency.Code)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	c, err := a.trader().Currencies.Find(code)
 	if err != nil {
 		return decimal.Decimal{}, err
 	}
 
-	return c.Value.Div(a.Currency.Value), nil
+	return a.trader().rate(from.Value, c.Value)
 }
 
 // ToCurrency converts the Amount to the given Currency. If the given Currency
 // is the same as the currency one of the Amount, the Amount is returned
-// directly
+// directly. The converted value is rounded to the result scale of the
+// Trader, if any (see Trader.WithResultScale)
 func (a Amount) ToCurrency(code CurrencyCode) (Amount, error) {
 	if a.Currency.Is(code) {
 		return a, nil
@@ -71,8 +118,12 @@ func (a Amount) ToCurrency(code CurrencyCode) (Amount, error) {
 	if err != nil {
 		return emptyAmount, err
 	}
+	v, err := a.trader().arithmetic().Mul(a.Value, rate)
+	if err != nil {
+		return emptyAmount, err
+	}
 
-	return a.Trader.NewAmount(a.Value.Mul(rate), code)
+	return a.trader().NewAmount(a.trader().resultScale.round(v), code)
 }
 
 // Add returns a new Amount corresponding to the sum of a and b. The
@@ -80,12 +131,23 @@ func (a Amount) ToCurrency(code CurrencyCode) (Amount, error) {
 // The returned Amount will use the Trader of a for any future operation.
 // If the trader of a and b is not the same, an error is returned
 func (a Amount) Add(b Amount) (Amount, error) {
-	if !a.Trader.Is(b.Trader) {
-		return emptyAmount, fmt.Errorf("The trader of a and b are not the same.")
+	if n, ok := a.addFixed(b, false); ok {
+		return n, nil
+	}
+	if !sameTrader(a.Trader, b.Trader) {
+		return emptyAmount, ErrTraderMismatch
 	}
 
-	n, _ := b.ToCurrency(a.Currency.Code)
-	return a.Trader.NewAmount(a.Value.Add(n.Value), a.Currency.Code)
+	n, err := b.ToCurrency(a.Currency.Code)
+	if err != nil {
+		return emptyAmount, err
+	}
+	v, err := a.trader().arithmetic().Add(a.Value, n.Value)
+	if err != nil {
+		return emptyAmount, err
+	}
+
+	return a.trader().NewAmount(v, a.Currency.Code)
 }
 
 // Sub returns a new Amount corresponding to the substraction of b from a. The
@@ -93,12 +155,23 @@ func (a Amount) Add(b Amount) (Amount, error) {
 // The returned Amount will use the Trader of a for any future operation.
 // If the trader of a and b is not the same, an error is returned
 func (a Amount) Sub(b Amount) (Amount, error) {
-	if !a.Trader.Is(b.Trader) {
-		return emptyAmount, fmt.Errorf("The trader of a and b are not the same.")
+	if n, ok := a.addFixed(b, true); ok {
+		return n, nil
+	}
+	if !sameTrader(a.Trader, b.Trader) {
+		return emptyAmount, ErrTraderMismatch
 	}
 
-	n, _ := b.ToCurrency(a.Currency.Code)
-	return a.Trader.NewAmount(a.Value.Sub(n.Value), a.Currency.Code)
+	n, err := b.ToCurrency(a.Currency.Code)
+	if err != nil {
+		return emptyAmount, err
+	}
+	v, err := a.trader().arithmetic().Sub(a.Value, n.Value)
+	if err != nil {
+		return emptyAmount, err
+	}
+
+	return a.trader().NewAmount(v, a.Currency.Code)
 }
 
 // Cmp compares a and b precisely in this order.
@@ -108,19 +181,87 @@ func (a Amount) Sub(b Amount) (Amount, error) {
 //  - > 0 if a is greater than b
 // To compare a and b, b is first converted to the currency of a
 func (a Amount) Cmp(b Amount) (int, error) {
-	if !a.Trader.Is(b.Trader) {
-		return 0, fmt.Errorf("The trader of a and b are not the same.")
+	if r, ok := a.cmpFixed(b); ok {
+		return r, nil
+	}
+	if !sameTrader(a.Trader, b.Trader) {
+		return 0, ErrTraderMismatch
+	}
+
+	n, err := b.ToCurrency(a.Currency.Code)
+	if err != nil {
+		return 0, err
+	}
+
+	return a.trader().arithmetic().Cmp(a.Value, n.Value)
+}
+
+// CmpSameCurrency compares a and b like Cmp does, but never converts b:
+// an error is returned if a and b are not in the same currency
+func (a Amount) CmpSameCurrency(b Amount) (int, error) {
+	if !a.SameCurrency(b) {
+		return 0, ErrCurrencyMismatch
+	}
+
+	return a.Value.Cmp(b.Value), nil
+}
+
+// SameCurrency returns true if a and b are in the same currency
+func (a Amount) SameCurrency(b Amount) bool {
+	return a.Currency.Is(b.Currency.Code)
+}
+
+// Equal returns true if a is equal to b, once b is converted to the
+// currency of a. It returns false if the trader of a and b is not the same
+func (a Amount) Equal(b Amount) bool {
+	r, err := a.Cmp(b)
+	return err == nil && r == 0
+}
+
+// LessThan returns true if a is smaller than b, once b is converted to the
+// currency of a. It returns false if the trader of a and b is not the same
+func (a Amount) LessThan(b Amount) bool {
+	r, err := a.Cmp(b)
+	return err == nil && r < 0
+}
+
+// GreaterThan returns true if a is greater than b, once b is converted to
+// the currency of a. It returns false if the trader of a and b is not the
+// same
+func (a Amount) GreaterThan(b Amount) bool {
+	r, err := a.Cmp(b)
+	return err == nil && r > 0
+}
+
+// ApproxEqual returns true if the difference between a and b is at most
+// tolerance, b and tolerance being first converted to the currency of a.
+// It returns false if the trader of a, b and tolerance is not the same
+func (a Amount) ApproxEqual(b, tolerance Amount) bool {
+	if !sameTrader(a.Trader, tolerance.Trader) {
+		return false
 	}
 
-	n, _ := b.ToCurrency(a.Currency.Code)
-	return a.Value.Cmp(n.Value), nil
+	diff, err := a.Sub(b)
+	if err != nil {
+		return false
+	}
+	t, err := tolerance.ToCurrency(a.Currency.Code)
+	if err != nil {
+		return false
+	}
+
+	d := diff.Value
+	if d.Cmp(decimal.New(0, 0)) < 0 {
+		d = decimal.New(0, 0).Sub(d)
+	}
+	return d.Cmp(t.Value) <= 0
 }
 
 // IsEmpty returns true if the amount is empty
 func (a Amount) IsEmpty() bool {
 	return a.Currency == emptyAmount.Currency &&
 		a.Value.Equals(emptyAmount.Value) &&
-		a.Trader.Is(emptyAmount.Trader)
+		sameTrader(a.Trader, emptyAmount.Trader)
 }
 
 // Int64 translates an amount into an in64 by adjusting its amount to the
@@ -1,6 +1,7 @@
 package trader
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/processout/decimal"
@@ -306,6 +307,81 @@ func TestCmp(t *testing.T) {
 	}
 }
 
+func TestAmount_CmpSameCurrency(t *testing.T) {
+	trader := getTrader()
+	amount, _ := trader.NewAmountFromString("2.3", "usd")
+	amount2, _ := trader.NewAmountFromString("2.3", "eur")
+
+	if amount.SameCurrency(amount2) {
+		t.Error("The amounts shouldn't have been in the same currency")
+	}
+	if _, err := amount.CmpSameCurrency(amount2); err != ErrCurrencyMismatch {
+		t.Error("There should have been an error")
+	}
+
+	amount2, _ = trader.NewAmountFromString("3.2", "usd")
+	if !amount.SameCurrency(amount2) {
+		t.Error("The amounts should have been in the same currency")
+	}
+	r, err := amount.CmpSameCurrency(amount2)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if r >= 0 {
+		t.Error("Answer should have been negative (2.3 < 3.2)")
+	}
+}
+
+func TestAmount_Comparisons(t *testing.T) {
+	trader := getTrader()
+	trader2 := getTrader2()
+	amount, _ := trader.NewAmountFromString("2", "usd")
+	amount2, _ := trader.NewAmountFromString("1.6", "eur")
+	amount3, _ := trader.NewAmountFromString("1.7", "eur")
+	amount4, _ := trader2.NewAmountFromString("2", "usd")
+
+	if !amount.Equal(amount2) || amount.Equal(amount3) {
+		t.Error("The amounts should have been compared after conversion")
+	}
+	if !amount.LessThan(amount3) || amount.LessThan(amount2) {
+		t.Error("2 USD should have been less than 1.7 EUR only")
+	}
+	if !amount3.GreaterThan(amount) || amount2.GreaterThan(amount) {
+		t.Error("1.7 EUR should have been greater than 2 USD only")
+	}
+	if amount.Equal(amount4) || amount.LessThan(amount4) || amount.GreaterThan(amount4) {
+		t.Error("Amounts with different traders should never compare")
+	}
+}
+
+func TestAmount_ApproxEqual(t *testing.T) {
+	trader := getTrader()
+	amount, _ := trader.NewAmountFromString("10.00", "usd")
+	amount2, _ := trader.NewAmountFromString("10.01", "usd")
+	cent, _ := trader.NewAmountFromString("0.01", "usd")
+	halfCent, _ := trader.NewAmountFromString("0.005", "usd")
+
+	if !amount.ApproxEqual(amount2, cent) || !amount2.ApproxEqual(amount, cent) {
+		t.Error("The amounts should have been within one cent")
+	}
+	if amount.ApproxEqual(amount2, halfCent) {
+		t.Error("The amounts shouldn't have been within half a cent")
+	}
+
+	// 8.008 EUR is 10.01 USD, and 0.008 EUR is 0.01 USD
+	amount3, _ := trader.NewAmountFromString("8.008", "eur")
+	eurCent, _ := trader.NewAmountFromString("0.008", "eur")
+	if !amount.ApproxEqual(amount3, eurCent) {
+		t.Error("The amounts should have been compared after conversion")
+	}
+
+	trader2 := getTrader2()
+	amount4, _ := trader2.NewAmountFromString("10.00", "usd")
+	if amount.ApproxEqual(amount4, cent) || amount4.ApproxEqual(amount4, cent) {
+		t.Error("Amounts with different traders should never be equal")
+	}
+}
+
 func TestAmount_Int64(t *testing.T) {
 	trader := getTrader()
 	amount, _ := trader.NewAmountFromString("2.3", "usd")
@@ -343,3 +419,161 @@ func TestAmount_String(t *testing.T) {
 		t.Error("The formatted amount was incorrect: " + amount.String(8))
 	}
 }
+
+func TestAmount_ToCurrencyRoundTrip(t *testing.T) {
+	usd, _ := NewCurrency("USD", decimal.NewFromFloat(1))
+	eur, _ := NewCurrency("EUR", decimal.NewFromFloat(0.91))
+	jpy, _ := NewCurrency("JPY", decimal.NewFromFloat(149.37))
+	base, _ := New(Currencies{usd, eur, jpy}, "usd")
+
+	// half returns half a unit of the given number of decimal places
+	half := func(places int32) decimal.Decimal {
+		return decimal.New(5, -places-1)
+	}
+	abs := func(d decimal.Decimal) decimal.Decimal {
+		if d.Cmp(decimal.New(0, 0)) < 0 {
+			return decimal.New(0, 0).Sub(d)
+		}
+		return d
+	}
+
+	tests := []struct {
+		trader Trader
+		rate   int32
+		scale  int32
+	}{
+		{base.WithRatePrecision(12), 12, -1},
+		{base.WithRatePrecision(6), 6, -1},
+		{base.WithRatePrecision(12).WithResultScale(2, RoundHalfUp), 12, 2},
+		{base.WithRatePrecision(12).WithResultScale(4, RoundHalfEven), 12, 4},
+	}
+
+	codes := []CurrencyCode{"USD", "EUR", "JPY"}
+	for _, test := range tests {
+		trader := test.trader
+		for _, from := range codes {
+			for _, to := range codes {
+				a, _ := trader.NewAmountFromString("1234.56", from)
+				for i := 0; i < 10; i++ {
+					n, _ := a.ToCurrency(to)
+					back, _ := n.ToCurrency(from)
+					r, _ := a.RateTo(to)
+					rinv, _ := n.RateTo(from)
+
+					// Each rate is off by half a unit of the rate precision,
+					// which is relative to the rate itself
+					one := decimal.New(1, 0)
+					bound := abs(a.Value).Mul(half(test.rate)).
+						Mul(one.Div(r).Add(one.Div(rinv)))
+					// Each rounding of the result is off by half a unit of
+					// the scale, and the first one is scaled by the inverse
+					// rate
+					if test.scale >= 0 {
+						bound = bound.Add(half(test.scale).Mul(rinv.Add(one)))
+					}
+
+					diff := abs(back.Value.Sub(a.Value))
+					if diff.Cmp(bound) > 0 {
+						t.Errorf("The round trip from %s to %s drifted by %s, more than %s",
+							from, to, diff, bound)
+					}
+					if test.scale >= 0 && back.Value.Exponent() < -test.scale {
+						t.Errorf("The round trip from %s to %s wasn't rounded: %s",
+							from, to, back.Value)
+					}
+					a = back
+				}
+			}
+		}
+	}
+}
+
+func TestAmount_SharedTrader(t *testing.T) {
+	trader := getTrader()
+	trader2 := getTrader()
+	amount, _ := trader.NewAmountFromString("2", "usd")
+	amount2, _ := trader.NewAmountFromString("1.6", "eur")
+	amount3, _ := trader2.NewAmountFromString("1.6", "eur")
+
+	if amount.Trader == &trader || amount.Trader != amount2.Trader {
+		t.Error("The amounts should have shared a copy of their trader")
+	}
+	if !amount.Equal(amount2) || !amount.Equal(amount3) {
+		t.Error("Amounts with traders having the same rates should compare")
+	}
+
+	var empty Amount
+	if _, err := empty.ToCurrency("usd"); err == nil {
+		t.Error("There should have been an error")
+	}
+	if _, err := amount.Add(empty); err != ErrTraderMismatch {
+		t.Error("There should have been an error")
+	}
+	if !empty.IsEmpty() {
+		t.Error("The amount should have been empty")
+	}
+}
+
+func TestAmount_SharedTraderUnmarshaled(t *testing.T) {
+	var trader Trader
+	b := `{"currencies":[{"code":"USD","value":"1"},{"code":"EUR","value":"0.8"}],` +
+		`"base_currency":{"code":"USD","value":"1"}}`
+	if err := json.Unmarshal([]byte(b), &trader); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	amount, _ := trader.NewAmountFromString("2", "usd")
+	amount2, _ := trader.NewAmountFromString("1.6", "eur")
+	if amount.Trader != amount2.Trader {
+		t.Error("The amounts should have shared a copy of their trader")
+	}
+
+	literal := Trader{
+		Currencies:   Currencies{trader.Currencies[0], trader.Currencies[1]},
+		BaseCurrency: trader.BaseCurrency,
+	}
+	amount3, _ := literal.NewAmountFromString("1", "usd")
+	if amount3.Trader != amount.Trader {
+		t.Error("The amounts should have shared a copy of their trader")
+	}
+
+	literal.Currencies[1].Value = decimal.NewFromFloat(0.9)
+	amount4, _ := literal.NewAmountFromString("1", "usd")
+	if amount4.Trader == amount.Trader {
+		t.Error("The new amounts should have used the new rates")
+	}
+	if n, _ := amount3.ToCurrency("eur"); n.Value.String() != "0.8" {
+		t.Error("The amount shouldn't have used the new rates: " + n.Value.String())
+	}
+}
+
+func TestAmount_FrozenTrader(t *testing.T) {
+	usd, _ := NewCurrency("USD", decimal.NewFromFloat(1))
+	sek, _ := NewCurrency("SEK", decimal.NewFromFloat(10))
+	trader, _ := New(Currencies{usd, sek}, "usd")
+	amount, _ := trader.NewAmountFromString("10", "usd")
+
+	sek, _ = NewCurrency("SEK", decimal.NewFromFloat(11))
+	trader, _ = New(Currencies{usd, sek}, "usd")
+	if n, _ := amount.ToCurrency("sek"); n.Value.String() != "100" {
+		t.Error("The amount shouldn't have used the new rates: " + n.Value.String())
+	}
+	if n, _ := trader.NewAmountFromString("10", "usd"); n.Trader == amount.Trader {
+		t.Error("The new amounts should have used the new rates")
+	}
+
+	trader.SetBaseCurrency("sek")
+	if !amount.Trader.BaseCurrency.Is("usd") {
+		t.Error("The base currency of the amount shouldn't have changed")
+	}
+
+	// The amounts of a Trader modified in place use its new rates
+	trader.Currencies[1].Value = decimal.NewFromFloat(12)
+	n, _ := trader.NewAmountFromString("10", "usd")
+	if n, _ := n.ToCurrency("sek"); n.Value.String() != "120" {
+		t.Error("The amount should have used the new rates: " + n.Value.String())
+	}
+	trader.Currencies[1].Value = decimal.NewFromFloat(13)
+	if n, _ := n.ToCurrency("sek"); n.Value.String() != "120" {
+		t.Error("The amount shouldn't have used the new rates: " + n.Value.String())
+	}
+}
@@ -0,0 +1,75 @@
+// Package arith abstracts the arithmetic behind amounts, so that it can be
+// done by the decimal package, by math/big, or on fixed-point int64 minor
+// units. Values are exchanged as decimal.Decimal, whatever the backend.
+package arith
+
+import (
+	"errors"
+
+	"github.com/processout/decimal"
+)
+
+// Backend does the arithmetic on values. Every operation may fail, e.g. if
+// a value can't be represented by the backend
+type Backend interface {
+	// Add returns x + y
+	Add(x, y decimal.Decimal) (decimal.Decimal, error)
+	// Sub returns x - y
+	Sub(x, y decimal.Decimal) (decimal.Decimal, error)
+	// Mul returns x * y, where y is a factor such as a rate
+	Mul(x, y decimal.Decimal) (decimal.Decimal, error)
+	// Div returns x / y, rounded half away from zero to the given number
+	// of decimal places. A backend may round to fewer places a quotient it
+	// can't represent with them, see Fixed
+	Div(x, y decimal.Decimal, places int32) (decimal.Decimal, error)
+	// Cmp returns -1, 0 or +1 if x is respectively smaller than, equal to
+	// or greater than y
+	Cmp(x, y decimal.Decimal) (int, error)
+}
+
+var (
+	// ErrDivisionByZero is the error returned when dividing by zero
+	ErrDivisionByZero = errors.New("Division by zero.")
+	// ErrOverflow is the error returned when a value is too large to be
+	// represented by the backend
+	ErrOverflow = errors.New("The value is too large to be represented.")
+	// ErrPrecision is the error returned when a value is too precise to be
+	// represented by the backend
+	ErrPrecision = errors.New("The value is too precise to be represented.")
+)
+
+// Decimal is the Backend using the decimal package. It never fails, except
+// when dividing by zero
+type Decimal struct{}
+
+// Add returns x + y
+func (Decimal) Add(x, y decimal.Decimal) (decimal.Decimal, error) {
+	return x.Add(y), nil
+}
+
+// Sub returns x - y
+func (Decimal) Sub(x, y decimal.Decimal) (decimal.Decimal, error) {
+	return x.Sub(y), nil
+}
+
+// Mul returns x * y
+func (Decimal) Mul(x, y decimal.Decimal) (decimal.Decimal, error) {
+	return x.Mul(y), nil
+}
+
+// Div returns x / y, rounded half away from zero to the given number of
+// decimal places. The decimal package truncates its quotients instead, so
+// they are rounded by Rat
+func (Decimal) Div(x, y decimal.Decimal, places int32) (decimal.Decimal, error) {
+	return Rat{}.Div(x, y, places)
+}
+
+// Cmp compares x and y
+func (Decimal) Cmp(x, y decimal.Decimal) (int, error) {
+	return x.Cmp(y), nil
+}
+
+// isZero returns true if d is zero
+func isZero(d decimal.Decimal) bool {
+	return d.Cmp(decimal.New(0, 0)) == 0
+}
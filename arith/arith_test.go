@@ -0,0 +1,20 @@
+package arith_test
+
+import (
+	"testing"
+
+	"gopkg.in/processout/trader.v2/arith"
+	"gopkg.in/processout/trader.v2/arith/arithtest"
+)
+
+func TestDecimal(t *testing.T) {
+	arithtest.Run(t, arith.Decimal{})
+}
+
+func TestRat(t *testing.T) {
+	arithtest.Run(t, arith.Rat{})
+}
+
+func TestFixed(t *testing.T) {
+	arithtest.Run(t, arith.Fixed{Places: 4})
+}
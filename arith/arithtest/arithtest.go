@@ -0,0 +1,136 @@
+// Package arithtest provides the conformance test suite every arith.Backend
+// must pass.
+package arithtest
+
+import (
+	"testing"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2/arith"
+)
+
+// Run runs the conformance test suite against the given Backend. The values
+// of the suite have at most 4 decimal places
+func Run(t *testing.T, b arith.Backend) {
+	t.Run("Add", func(t *testing.T) { testAdd(t, b) })
+	t.Run("Sub", func(t *testing.T) { testSub(t, b) })
+	t.Run("Mul", func(t *testing.T) { testMul(t, b) })
+	t.Run("Div", func(t *testing.T) { testDiv(t, b) })
+	t.Run("Cmp", func(t *testing.T) { testCmp(t, b) })
+}
+
+// op is a binary operation of a Backend
+type op func(x, y decimal.Decimal) (decimal.Decimal, error)
+
+// check runs op on each of the cases, made of x, y and the expected result
+func check(t *testing.T, name string, f op, cases [][3]string) {
+	for _, c := range cases {
+		x, y, expected := parse(c[0]), parse(c[1]), parse(c[2])
+
+		r, err := f(x, y)
+		if err != nil {
+			t.Errorf("%s %s %s: there shouldn't have been an error: %s", c[0], name, c[1], err)
+			continue
+		}
+		if r.Cmp(expected) != 0 {
+			t.Errorf("%s %s %s should have given %s, got %s", c[0], name, c[1], c[2], r)
+		}
+	}
+}
+
+func testAdd(t *testing.T, b arith.Backend) {
+	check(t, "+", b.Add, [][3]string{
+		{"1.5", "2.25", "3.75"},
+		{"-1.5", "1.5", "0"},
+		{"0.0001", "0.0002", "0.0003"},
+		{"1000000", "-0.0001", "999999.9999"},
+	})
+}
+
+func testSub(t *testing.T, b arith.Backend) {
+	check(t, "-", b.Sub, [][3]string{
+		{"1", "2.5", "-1.5"},
+		{"-1.5", "-1.5", "0"},
+		{"10.0001", "0.0001", "10"},
+	})
+}
+
+func testMul(t *testing.T, b arith.Backend) {
+	check(t, "*", b.Mul, [][3]string{
+		{"1.5", "2.25", "3.375"},
+		{"-2", "0.5", "-1"},
+		{"-2", "-0.5", "1"},
+		{"0", "123.4", "0"},
+		{"100", "0.0001", "0.01"},
+	})
+}
+
+func testDiv(t *testing.T, b arith.Backend) {
+	cases := []struct {
+		x, y     string
+		places   int32
+		expected string
+	}{
+		{"1", "3", 4, "0.3333"},
+		{"2", "3", 2, "0.67"},
+		{"-2", "3", 2, "-0.67"},
+		{"10", "4", 0, "3"},
+		{"-10", "4", 0, "-3"},
+		{"1", "8", 3, "0.125"},
+		{"1", "8", 2, "0.13"},
+		{"1.5", "0.5", 2, "3"},
+		// The rates between currencies of very different values, e.g.
+		// USD and KRW
+		{"1350", "1", 16, "1350"},
+		{"1", "1350", 16, "0.0007407407407407"},
+	}
+
+	for _, c := range cases {
+		r, err := b.Div(parse(c.x), parse(c.y), c.places)
+		if err != nil {
+			t.Errorf("%s / %s: there shouldn't have been an error: %s", c.x, c.y, err)
+			continue
+		}
+		if r.Cmp(parse(c.expected)) != 0 {
+			t.Errorf("%s / %s at %d places should have given %s, got %s",
+				c.x, c.y, c.places, c.expected, r)
+		}
+	}
+
+	if _, err := b.Div(parse("1"), parse("0"), 2); err != arith.ErrDivisionByZero {
+		t.Errorf("Dividing by zero should have returned ErrDivisionByZero, got %v", err)
+	}
+}
+
+func testCmp(t *testing.T, b arith.Backend) {
+	cases := []struct {
+		x, y     string
+		expected int
+	}{
+		{"1.50", "1.5", 0},
+		{"-1", "1", -1},
+		{"2", "1.9999", 1},
+		{"0", "-0.0001", 1},
+	}
+
+	for _, c := range cases {
+		r, err := b.Cmp(parse(c.x), parse(c.y))
+		if err != nil {
+			t.Errorf("Comparing %s and %s: there shouldn't have been an error: %s", c.x, c.y, err)
+			continue
+		}
+		if r != c.expected {
+			t.Errorf("Comparing %s and %s should have given %d, got %d", c.x, c.y, c.expected, r)
+		}
+	}
+}
+
+// parse parses the given decimal, which must be valid
+func parse(s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
@@ -0,0 +1,241 @@
+package arith
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/processout/decimal"
+)
+
+// maxPow10 is the greatest power of ten that fits in an uint64
+const maxPow10 = 19
+
+// Fixed is the Backend doing the arithmetic on int64 minor units, i.e. on
+// values scaled by 10^Places, using 128 bit intermediate results. Values
+// with more decimal places than Places, or too large to fit in an int64
+// once scaled, are refused.
+//
+// Places applies to all the amounts, whatever their currency: it should be
+// the greatest number of decimal places of the currencies used, e.g. 3 if
+// amounts are converted to KWD, as the results of Mul, and thus of the
+// conversions, are rounded to Places
+type Fixed struct {
+	// Places is the number of decimal places of the values, e.g. 2 for
+	// cents. It must be between 0 and 18
+	Places int32
+}
+
+// Add returns x + y
+func (f Fixed) Add(x, y decimal.Decimal) (decimal.Decimal, error) {
+	a, b, err := f.units2(x, y)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return f.add(a, b)
+}
+
+// Sub returns x - y
+func (f Fixed) Sub(x, y decimal.Decimal) (decimal.Decimal, error) {
+	a, b, err := f.units2(x, y)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return f.add(a, -b)
+}
+
+// Mul returns x * y, rounded half away from zero to Places. The factor y
+// may have more decimal places than Places, e.g. if it is a rate, as long
+// as its digits fit in an int64
+func (f Fixed) Mul(x, y decimal.Decimal) (decimal.Decimal, error) {
+	a, err := f.units(x)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	p := places(y)
+	c, err := Fixed{Places: p}.units(y)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	hi, lo := bits.Mul64(abs(a), abs(c))
+	q, err := divRound(hi, lo, pow10(p))
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return decimal.New(sign(a, c)*q, -f.Places), nil
+}
+
+// Div returns x / y, rounded half away from zero to the given number of
+// decimal places, which must be at most 18. Like the factor of Mul, x and
+// y may have more decimal places than Places, e.g. if they are the values
+// of currencies, as long as their digits fit in an int64. If the quotient
+// doesn't fit in an int64 with the given places, it is rounded to the
+// greatest number of places it fits with, e.g. 1350 / 1 at 16 places is
+// 1350 at 15 places
+func (f Fixed) Div(x, y decimal.Decimal, p int32) (decimal.Decimal, error) {
+	if p < 0 {
+		p = 0
+	}
+	if p >= maxPow10 {
+		return decimal.Decimal{}, ErrPrecision
+	}
+
+	px, py := places(x), places(y)
+	a, err := Fixed{Places: px}.units(x)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	b, err := Fixed{Places: py}.units(y)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	if b == 0 {
+		return decimal.Decimal{}, ErrDivisionByZero
+	}
+
+	for ; ; p-- {
+		q, err := div(abs(a), abs(b), p+py-px)
+		if err == ErrOverflow && p > 0 {
+			continue
+		}
+		if err != nil {
+			return decimal.Decimal{}, err
+		}
+
+		return decimal.New(sign(a, b)*q, -p), nil
+	}
+}
+
+// div returns n * 10^shift / d, rounded half away from zero. An error is
+// returned if it doesn't fit in an int64
+func div(n, d uint64, shift int32) (int64, error) {
+	var hi, lo uint64
+	if shift >= 0 {
+		if shift > maxPow10 {
+			return 0, ErrOverflow
+		}
+		hi, lo = bits.Mul64(n, pow10(shift))
+	} else {
+		if -shift > maxPow10 {
+			return 0, nil
+		}
+		dhi, dlo := bits.Mul64(d, pow10(-shift))
+		if dhi != 0 {
+			// The quotient is smaller than a half
+			return 0, nil
+		}
+		lo, d = n, dlo
+	}
+
+	return divRound(hi, lo, d)
+}
+
+// Cmp compares x and y
+func (f Fixed) Cmp(x, y decimal.Decimal) (int, error) {
+	a, b, err := f.units2(x, y)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case a < b:
+		return -1, nil
+	case a > b:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// units returns d in minor units
+func (f Fixed) units(d decimal.Decimal) (int64, error) {
+	if f.Places < 0 || f.Places >= maxPow10 {
+		return 0, ErrPrecision
+	}
+
+	r := d.Mul(decimal.New(1, f.Places)).Rat()
+	if !r.IsInt() {
+		return 0, ErrPrecision
+	}
+	n := r.Num()
+	if !n.IsInt64() || n.Int64() == math.MinInt64 {
+		return 0, ErrOverflow
+	}
+
+	return n.Int64(), nil
+}
+
+// units2 returns x and y in minor units
+func (f Fixed) units2(x, y decimal.Decimal) (int64, int64, error) {
+	a, err := f.units(x)
+	if err != nil {
+		return 0, 0, err
+	}
+	b, err := f.units(y)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return a, b, nil
+}
+
+// add returns a + b, in minor units, as a decimal
+func (f Fixed) add(a, b int64) (decimal.Decimal, error) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < -math.MaxInt64-b) {
+		return decimal.Decimal{}, ErrOverflow
+	}
+
+	return decimal.New(a+b, -f.Places), nil
+}
+
+// divRound divides the 128 bit number hi, lo by d, and rounds the result
+// half away from zero. An error is returned if it doesn't fit in an int64
+func divRound(hi, lo, d uint64) (int64, error) {
+	if hi >= d {
+		return 0, ErrOverflow
+	}
+
+	q, r := bits.Div64(hi, lo, d)
+	if q > math.MaxInt64 {
+		return 0, ErrOverflow
+	}
+	if r >= d-r {
+		q++
+	}
+	if q > math.MaxInt64 {
+		return 0, ErrOverflow
+	}
+
+	return int64(q), nil
+}
+
+// pow10 returns 10^p, p being at most maxPow10
+func pow10(p int32) uint64 {
+	n := uint64(1)
+	for i := int32(0); i < p; i++ {
+		n *= 10
+	}
+
+	return n
+}
+
+// abs returns the absolute value of n, which must not be math.MinInt64
+func abs(n int64) uint64 {
+	if n < 0 {
+		return uint64(-n)
+	}
+
+	return uint64(n)
+}
+
+// sign returns the sign of a * b, as -1 or 1
+func sign(a, b int64) int64 {
+	if (a < 0) != (b < 0) {
+		return -1
+	}
+
+	return 1
+}
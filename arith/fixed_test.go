@@ -0,0 +1,105 @@
+package arith
+
+import (
+	"math"
+	"testing"
+
+	"github.com/processout/decimal"
+)
+
+func TestFixed_Errors(t *testing.T) {
+	f := Fixed{Places: 2}
+	cent := decimal.NewFromFloat(0.01)
+	max := decimal.New(math.MaxInt64, -2)
+
+	if _, err := f.Add(decimal.NewFromFloat(0.001), cent); err != ErrPrecision {
+		t.Error("The too precise value should have been refused")
+	}
+	if _, err := f.Add(decimal.New(math.MaxInt64, 0), cent); err != ErrOverflow {
+		t.Error("The too large value should have been refused")
+	}
+	if _, err := f.Add(max, cent); err != ErrOverflow {
+		t.Error("The sum should have overflowed")
+	}
+	if _, err := f.Sub(decimal.New(0, 0).Sub(max), cent); err != ErrOverflow {
+		t.Error("The difference should have overflowed")
+	}
+	if r, err := f.Sub(max, cent); err != nil || r.Cmp(decimal.New(math.MaxInt64-1, -2)) != 0 {
+		t.Error("The difference shouldn't have overflowed")
+	}
+	if _, err := f.Mul(max, decimal.New(2, 0)); err != ErrOverflow {
+		t.Error("The product should have overflowed")
+	}
+	if _, err := f.Div(max, decimal.New(1, -3), 2); err != ErrOverflow {
+		t.Error("The quotient should have overflowed")
+	}
+	if r, err := f.Div(max, cent, 2); err != nil || r.Cmp(decimal.New(math.MaxInt64, 0)) != 0 {
+		t.Error("The quotient should have been rounded to the places it fits with")
+	}
+	if _, err := f.Div(cent, cent, 19); err != ErrPrecision {
+		t.Error("The too precise quotient should have been refused")
+	}
+	if _, err := (Fixed{Places: 19}).Add(cent, cent); err != ErrPrecision {
+		t.Error("The too precise backend should have been refused")
+	}
+}
+
+func TestFixed_Mul(t *testing.T) {
+	f := Fixed{Places: 2}
+
+	// 12.34 * 0.8123456789012345 = 10.02434567...
+	rate, _ := decimal.NewFromString("0.8123456789012345")
+	r, err := f.Mul(decimal.NewFromFloat(12.34), rate)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if r.String() != "10.02" {
+		t.Errorf("The product should have been rounded to cents, got %s", r)
+	}
+
+	// 0.05 * 0.5 = 0.025, rounded away from zero
+	r, _ = f.Mul(decimal.NewFromFloat(-0.05), decimal.NewFromFloat(0.5))
+	if r.String() != "-0.03" {
+		t.Errorf("The product should have been rounded away from zero, got %s", r)
+	}
+
+	tiny, _ := decimal.NewFromString("0.00000000000000000001")
+	if _, err := f.Mul(decimal.New(1, 0), tiny); err != ErrPrecision {
+		t.Error("The too precise factor should have been refused")
+	}
+}
+
+func TestFixed_Div(t *testing.T) {
+	f := Fixed{Places: 2}
+
+	cases := []struct {
+		x, y     string
+		places   int32
+		expected string
+	}{
+		// The operands may have more decimal places than the backend, e.g.
+		// the values of currencies
+		{"1", "0.8", 16, "1.25"},
+		{"0.377", "1.2345", 6, "0.305387"},
+		{"-110.123456", "0.8", 4, "-137.6543"},
+		{"1", "3", 0, "0"},
+		{"2", "3", 0, "1"},
+		{"0.000000000000000001", "100000000000000000", 2, "0"},
+	}
+	for _, c := range cases {
+		x, _ := decimal.NewFromString(c.x)
+		y, _ := decimal.NewFromString(c.y)
+		r, err := f.Div(x, y, c.places)
+		if err != nil {
+			t.Errorf("%s / %s: there shouldn't have been an error: %s", c.x, c.y, err)
+			continue
+		}
+		if e, _ := decimal.NewFromString(c.expected); r.Cmp(e) != 0 {
+			t.Errorf("%s / %s should have been %s, got %s", c.x, c.y, c.expected, r)
+		}
+	}
+
+	if _, err := f.Div(decimal.New(1, 0), decimal.New(0, -3), 2); err != ErrDivisionByZero {
+		t.Error("Dividing by zero should have returned ErrDivisionByZero")
+	}
+}
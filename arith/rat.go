@@ -0,0 +1,73 @@
+package arith
+
+import (
+	"math/big"
+
+	"github.com/processout/decimal"
+)
+
+// Rat is the Backend using math/big.Rat. Its results are exact, except for
+// divisions which are rounded to the requested number of decimal places
+type Rat struct{}
+
+// Add returns x + y
+func (Rat) Add(x, y decimal.Decimal) (decimal.Decimal, error) {
+	r := new(big.Rat).Add(x.Rat(), y.Rat())
+	return fromRat(r, maxPlaces(places(x), places(y)))
+}
+
+// Sub returns x - y
+func (Rat) Sub(x, y decimal.Decimal) (decimal.Decimal, error) {
+	r := new(big.Rat).Sub(x.Rat(), y.Rat())
+	return fromRat(r, maxPlaces(places(x), places(y)))
+}
+
+// Mul returns x * y
+func (Rat) Mul(x, y decimal.Decimal) (decimal.Decimal, error) {
+	r := new(big.Rat).Mul(x.Rat(), y.Rat())
+	return fromRat(r, places(x)+places(y))
+}
+
+// Div returns x / y, rounded half away from zero to the given number of
+// decimal places
+func (Rat) Div(x, y decimal.Decimal, p int32) (decimal.Decimal, error) {
+	if isZero(y) {
+		return decimal.Decimal{}, ErrDivisionByZero
+	}
+
+	r := new(big.Rat).Quo(x.Rat(), y.Rat())
+	return fromRat(r, p)
+}
+
+// Cmp compares x and y
+func (Rat) Cmp(x, y decimal.Decimal) (int, error) {
+	return x.Rat().Cmp(y.Rat()), nil
+}
+
+// places returns the number of decimal places of d
+func places(d decimal.Decimal) int32 {
+	if exp := d.Exponent(); exp < 0 {
+		return -exp
+	}
+
+	return 0
+}
+
+// maxPlaces returns the greatest of x and y
+func maxPlaces(x, y int32) int32 {
+	if x > y {
+		return x
+	}
+
+	return y
+}
+
+// fromRat converts r to a decimal, rounded half away from zero to the
+// given number of decimal places
+func fromRat(r *big.Rat, p int32) (decimal.Decimal, error) {
+	if p < 0 {
+		p = 0
+	}
+
+	return decimal.NewFromString(r.FloatString(int(p)))
+}
@@ -0,0 +1,158 @@
+package trader
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/processout/decimal"
+)
+
+// Bag holds values in several currencies without converting them, e.g.
+// 10 USD + 5 EUR. Currencies with a balance of zero are not kept in the Bag
+type Bag map[CurrencyCode]decimal.Decimal
+
+// NewBag creates a new Bag containing the sum of the given amounts
+func NewBag(amounts ...Amount) Bag {
+	b := Bag{}
+	for _, a := range amounts {
+		b.add(a.Currency.Code, a.Value)
+	}
+
+	return b
+}
+
+// add adds the given value to the balance of the given currency code
+func (b Bag) add(code CurrencyCode, v decimal.Decimal) {
+	code = code.format()
+	if c, ok := b[code]; ok {
+		v = c.Add(v)
+	}
+
+	if v.Cmp(decimal.New(0, 0)) == 0 {
+		delete(b, code)
+		return
+	}
+	b[code] = v
+}
+
+// copy returns a copy of the Bag
+func (b Bag) copy() Bag {
+	n := make(Bag, len(b))
+	for k, v := range b {
+		n[k] = v
+	}
+
+	return n
+}
+
+// Add returns a new Bag corresponding to b with the Amount a added to the
+// balance of its currency. No conversion is performed
+func (b Bag) Add(a Amount) Bag {
+	n := b.copy()
+	n.add(a.Currency.Code, a.Value)
+	return n
+}
+
+// Sub returns a new Bag corresponding to b with the Amount a substracted
+// from the balance of its currency. No conversion is performed
+func (b Bag) Sub(a Amount) Bag {
+	n := b.copy()
+	n.add(a.Currency.Code, decimal.New(0, 0).Sub(a.Value))
+	return n
+}
+
+// Neg returns a new Bag where every balance of b is negated
+func (b Bag) Neg() Bag {
+	n := make(Bag, len(b))
+	for k, v := range b {
+		n[k] = decimal.New(0, 0).Sub(v)
+	}
+
+	return n
+}
+
+// Codes returns the currency codes held in the Bag, sorted alphabetically
+func (b Bag) Codes() []CurrencyCode {
+	codes := make([]CurrencyCode, 0, len(b))
+	for k := range b {
+		codes = append(codes, k)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+
+	return codes
+}
+
+// Amounts returns the balances of the Bag as amounts created by the given
+// Trader, sorted by currency code. An error is returned if one of the
+// currencies is not supported by the Trader
+func (b Bag) Amounts(t *Trader) ([]Amount, error) {
+	if t == nil {
+		t = &emptyTrader
+	}
+
+	amounts := make([]Amount, 0, len(b))
+	for _, code := range b.Codes() {
+		a, err := t.NewAmount(b[code], code)
+		if err != nil {
+			return nil, err
+		}
+		amounts = append(amounts, a)
+	}
+
+	return amounts, nil
+}
+
+// Total converts every balance of the Bag to the given currency using the
+// given Trader, and returns their sum. An error is returned if one of the
+// currencies is not supported by the Trader
+func (b Bag) Total(t *Trader, code CurrencyCode) (Amount, error) {
+	if t == nil {
+		return emptyAmount, CurrencyNotFoundError{code}
+	}
+
+	total, err := t.NewAmount(decimal.New(0, 0), code)
+	if err != nil {
+		return emptyAmount, err
+	}
+
+	amounts, err := b.Amounts(t)
+	if err != nil {
+		return emptyAmount, err
+	}
+	for _, a := range amounts {
+		total, err = total.Add(a)
+		if err != nil {
+			return emptyAmount, err
+		}
+	}
+
+	return total, nil
+}
+
+// MarshalJSON marshals the Bag as a JSON object of the balances, indexed
+// by currency code
+func (b Bag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[CurrencyCode]decimal.Decimal(b))
+}
+
+// UnmarshalJSON unmarshals a JSON object of balances indexed by currency
+// code. An error is returned if one of the codes is not part of ISO 4217
+func (b *Bag) UnmarshalJSON(data []byte) error {
+	m := map[CurrencyCode]decimal.Decimal{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	n := make(Bag, len(m))
+	for k, v := range m {
+		if !k.Verify() {
+			return UnknownCurrencyError{k}
+		}
+		n.add(k, v)
+	}
+
+	*b = n
+	return nil
+}
@@ -0,0 +1,120 @@
+package trader
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBag_Add(t *testing.T) {
+	trader := getTrader()
+	usd, _ := trader.NewAmountFromString("10", "usd")
+	eur, _ := trader.NewAmountFromString("5", "eur")
+
+	b := NewBag(usd)
+	b2 := b.Add(eur).Add(usd)
+	if len(b) != 1 {
+		t.Error("The original bag shouldn't have been modified")
+	}
+	if len(b2) != 2 {
+		t.Error("The bag should have contained two currencies")
+	}
+	if b2["USD"].StringFixed(2) != "20.00" {
+		t.Error("The USD balance was wrong: " + b2["USD"].String())
+	}
+	if b2["EUR"].StringFixed(2) != "5.00" {
+		t.Error("The EUR balance was wrong: " + b2["EUR"].String())
+	}
+}
+
+func TestBag_Sub(t *testing.T) {
+	trader := getTrader()
+	usd, _ := trader.NewAmountFromString("10", "usd")
+	eur, _ := trader.NewAmountFromString("5", "eur")
+
+	b := NewBag(usd).Sub(eur)
+	if b["EUR"].StringFixed(2) != "-5.00" {
+		t.Error("The EUR balance was wrong: " + b["EUR"].String())
+	}
+
+	b = b.Sub(usd)
+	if _, ok := b["USD"]; ok {
+		t.Error("The empty USD balance should have been removed")
+	}
+}
+
+func TestBag_Neg(t *testing.T) {
+	trader := getTrader()
+	usd, _ := trader.NewAmountFromString("10", "usd")
+	eur, _ := trader.NewAmountFromString("-5", "eur")
+
+	b := NewBag(usd, eur).Neg()
+	if b["USD"].StringFixed(2) != "-10.00" || b["EUR"].StringFixed(2) != "5.00" {
+		t.Error("The balances should have been negated")
+	}
+}
+
+func TestBag_Amounts(t *testing.T) {
+	trader := getTrader()
+	usd, _ := trader.NewAmountFromString("10", "usd")
+	eur, _ := trader.NewAmountFromString("5", "eur")
+
+	amounts, err := NewBag(usd, eur).Amounts(&trader)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if len(amounts) != 2 || !amounts[0].Currency.Is("eur") ||
+		!amounts[1].Currency.Is("usd") {
+
+		t.Error("The amounts should have been sorted by currency code")
+	}
+
+	trader2 := getTrader2()
+	_, err = NewBag(usd, eur).Amounts(&trader2)
+	if err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestBag_Total(t *testing.T) {
+	trader := getTrader()
+	usd, _ := trader.NewAmountFromString("10", "usd")
+	eur, _ := trader.NewAmountFromString("4", "eur")
+
+	total, err := NewBag(usd, eur).Total(&trader, "usd")
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if total.String(2) != "15.00" || !total.Currency.Is("usd") {
+		t.Error("The total was incorrectly computed: " + total.String(2))
+	}
+
+	_, err = NewBag(usd, eur).Total(&trader, "gel")
+	if err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestBag_JSON(t *testing.T) {
+	trader := getTrader()
+	usd, _ := trader.NewAmountFromString("10", "usd")
+	eur, _ := trader.NewAmountFromString("5", "eur")
+
+	data, err := json.Marshal(NewBag(usd, eur))
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+
+	var b Bag
+	if err := json.Unmarshal(data, &b); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if len(b) != 2 || b["USD"].StringFixed(2) != "10.00" ||
+		b["EUR"].StringFixed(2) != "5.00" {
+
+		t.Error("The bag was incorrectly unmarshaled: " + string(data))
+	}
+
+	if err := json.Unmarshal([]byte(`{"lel":"1"}`), &b); err == nil {
+		t.Error("There should have been an error")
+	}
+}
@@ -0,0 +1,256 @@
+// Package batch converts a column of amounts in mixed currencies to a single
+// currency, streaming CSV or JSON Lines rows. Rows that can't be converted
+// are reported in the output instead of aborting the conversion.
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/processout/trader.v2"
+)
+
+// Format is the format of the rows to convert
+type Format int
+
+const (
+	// CSV rows, with a header naming the columns
+	CSV Format = iota
+	// JSONL rows, one JSON object per line
+	JSONL
+)
+
+// ParseFormat returns the Format with the given name, csv or jsonl
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "csv":
+		return CSV, nil
+	case "jsonl":
+		return JSONL, nil
+	}
+
+	return CSV, fmt.Errorf("The format %s is not supported.", s)
+}
+
+// Options are the options of a batch conversion
+type Options struct {
+	Format Format
+	// AmountField is the name of the column holding the amounts
+	AmountField string
+	// CurrencyField is the name of the column holding the currency codes
+	CurrencyField string
+	// Target is the currency the amounts are converted to
+	Target trader.CurrencyCode
+	// Rounding is the mode used to round the converted amounts to the
+	// decimal places of the target currency
+	Rounding trader.RoundingMode
+	// OutputField is the name of the column receiving the converted amounts.
+	// It defaults to "converted"
+	OutputField string
+	// ErrorField is the name of the column receiving the errors of the rows
+	// that could not be converted. It defaults to "error"
+	ErrorField string
+}
+
+// RowError is the error of a row that could not be converted
+type RowError struct {
+	// Row is the number of the row, starting at 1 for the first data row
+	Row int
+	Err error
+}
+
+// Error to implement error interface
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Row, e.Err)
+}
+
+// Result is the summary of a batch conversion
+type Result struct {
+	// Rows is the number of rows read
+	Rows int
+	// Converted is the number of rows successfully converted
+	Converted int
+	// Errors contains the errors of the rows that could not be converted
+	Errors []RowError
+}
+
+// Convert reads the rows from r, converts their amounts to the target
+// currency using the Trader t, and writes the rows to w with the converted
+// amounts and the errors added. An error is only returned if the rows could
+// not be read or written; conversion errors are reported per row in the
+// output and in the Result
+func Convert(t trader.Trader, r io.Reader, w io.Writer, opts Options) (Result, error) {
+	if opts.OutputField == "" {
+		opts.OutputField = "converted"
+	}
+	if opts.ErrorField == "" {
+		opts.ErrorField = "error"
+	}
+	if _, err := t.Currencies.Find(opts.Target); err != nil {
+		return Result{}, err
+	}
+
+	c := converter{
+		trader: t,
+		opts:   opts,
+	}
+	switch opts.Format {
+	case CSV:
+		return c.csv(r, w)
+	case JSONL:
+		return c.jsonl(r, w)
+	}
+
+	return Result{}, fmt.Errorf("The format %d is not supported.", opts.Format)
+}
+
+// converter converts the rows of a batch
+type converter struct {
+	trader trader.Trader
+	opts   Options
+	result Result
+}
+
+// convert converts the given amount, and records the result of the row
+func (c *converter) convert(amount, code string) (string, error) {
+	c.result.Rows++
+
+	a, err := c.trader.NewAmountFromString(strings.TrimSpace(amount),
+		trader.CurrencyCode(strings.TrimSpace(code)))
+	if err == nil {
+		a, err = a.ToCurrency(c.opts.Target)
+	}
+	if err != nil {
+		c.result.Errors = append(c.result.Errors, RowError{c.result.Rows, err})
+		return "", err
+	}
+
+	c.result.Converted++
+	places := a.Currency.DecimalPlaces()
+	if places < 0 {
+		return a.Value.String(), nil
+	}
+	return a.Round(c.opts.Rounding).String(int32(places)), nil
+}
+
+// fail records a row that could not be read, and returns its error
+func (c *converter) fail(err error) error {
+	c.result.Rows++
+	c.result.Errors = append(c.result.Errors, RowError{c.result.Rows, err})
+	return err
+}
+
+// csv converts CSV rows. The converted rows are flushed even if an error
+// occurs
+func (c *converter) csv(r io.Reader, w io.Writer) (Result, error) {
+	cw := csv.NewWriter(w)
+	err := c.csvRows(r, cw)
+	cw.Flush()
+	if err == nil {
+		err = cw.Error()
+	}
+
+	return c.result, err
+}
+
+// csvRows converts the CSV rows read from r, and writes them to cw. The rows
+// that are malformed or lack a column are reported like conversion errors
+func (c *converter) csvRows(r io.Reader, cw *csv.Writer) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return err
+	}
+	amount, code := -1, -1
+	for i, h := range header {
+		switch h {
+		case c.opts.AmountField:
+			amount = i
+		case c.opts.CurrencyField:
+			code = i
+		}
+	}
+	if amount < 0 || code < 0 {
+		return fmt.Errorf("The columns %s and %s are required.",
+			c.opts.AmountField, c.opts.CurrencyField)
+	}
+	if err := cw.Write(append(header, c.opts.OutputField, c.opts.ErrorField)); err != nil {
+		return err
+	}
+
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		var v string
+		if perr, ok := err.(*csv.ParseError); ok {
+			rec = make([]string, len(header))
+			err = c.fail(perr)
+		} else if err != nil {
+			return err
+		} else if len(rec) <= amount || len(rec) <= code {
+			err = c.fail(fmt.Errorf("The row has no %s or %s column.",
+				c.opts.AmountField, c.opts.CurrencyField))
+		} else {
+			v, err = c.convert(rec[amount], rec[code])
+		}
+
+		msg := ""
+		if err != nil {
+			msg = err.Error()
+		}
+		if err := cw.Write(append(rec, v, msg)); err != nil {
+			return err
+		}
+	}
+}
+
+// jsonl converts JSON Lines rows. The amounts may be JSON strings or numbers
+func (c *converter) jsonl(r io.Reader, w io.Writer) (Result, error) {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	bw := bufio.NewWriter(w)
+	e := json.NewEncoder(bw)
+
+	for s.Scan() {
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		row := map[string]interface{}{}
+		d := json.NewDecoder(bytes.NewReader(line))
+		d.UseNumber()
+		if err := d.Decode(&row); err != nil {
+			row[c.opts.ErrorField] = c.fail(err).Error()
+		} else {
+			v, err := c.convert(fmt.Sprint(row[c.opts.AmountField]),
+				fmt.Sprint(row[c.opts.CurrencyField]))
+			if err != nil {
+				row[c.opts.ErrorField] = err.Error()
+			} else {
+				row[c.opts.OutputField] = v
+			}
+		}
+
+		if err := e.Encode(row); err != nil {
+			bw.Flush()
+			return c.result, err
+		}
+	}
+	if err := s.Err(); err != nil {
+		bw.Flush()
+		return c.result, err
+	}
+
+	return c.result, bw.Flush()
+}
@@ -0,0 +1,155 @@
+package batch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+func getTrader() trader.Trader {
+	c1, _ := trader.NewCurrency("USD", decimal.NewFromFloat(1))
+	c2, _ := trader.NewCurrency("EUR", decimal.NewFromFloat(0.8))
+	c3, _ := trader.NewCurrency("JPY", decimal.NewFromFloat(100))
+	c4, _ := trader.NewCurrency("XAU", decimal.NewFromFloat(0.0005))
+	t, _ := trader.New(trader.Currencies{c1, c2, c3, c4}, "usd")
+	return t
+}
+
+func TestParseFormat(t *testing.T) {
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("There should have been an error")
+	}
+	if f, err := ParseFormat("JSONL"); err != nil || f != JSONL {
+		t.Error("The wrong format was returned")
+	}
+}
+
+func TestConvert_CSV(t *testing.T) {
+	opts := Options{
+		AmountField:   "amount",
+		CurrencyField: "currency",
+		Target:        "eur",
+		Rounding:      trader.RoundHalfEven,
+	}
+	in := "id,amount,currency\n1,10.00,usd\n2,lel,usd\n3,100,gel\n4,1,jpy\n"
+
+	var out bytes.Buffer
+	res, err := Convert(getTrader(), strings.NewReader(in), &out, opts)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if res.Rows != 4 || res.Converted != 2 || len(res.Errors) != 2 {
+		t.Errorf("The result was incorrect: %+v", res)
+	}
+	if res.Errors[0].Row != 2 || res.Errors[1].Row != 3 {
+		t.Error("The errors should have been reported with their row")
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	if lines[0] != "id,amount,currency,converted,error" {
+		t.Error("The header was incorrectly written: " + lines[0])
+	}
+	if lines[1] != "1,10.00,usd,8.00," {
+		t.Error("The row was incorrectly converted: " + lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "2,lel,usd,,") || len(lines[2]) == len("2,lel,usd,,") {
+		t.Error("The row error should have been reported: " + lines[2])
+	}
+	if lines[4] != "4,1,jpy,0.01," {
+		t.Error("The row was incorrectly rounded: " + lines[4])
+	}
+
+	_, err = Convert(getTrader(), strings.NewReader("id,value\n"), &out, opts)
+	if err == nil {
+		t.Error("There should have been an error")
+	}
+
+	opts.Target = "gel"
+	_, err = Convert(getTrader(), strings.NewReader(in), &out, opts)
+	if err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestConvert_CSVMalformed(t *testing.T) {
+	opts := Options{
+		AmountField:   "amount",
+		CurrencyField: "currency",
+		Target:        "eur",
+	}
+	in := "id,amount,currency\n1,10.00,usd\n2,5\n3,1\"0,usd\n4,1,usd\n"
+
+	var out bytes.Buffer
+	res, err := Convert(getTrader(), strings.NewReader(in), &out, opts)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if res.Rows != 4 || res.Converted != 2 || len(res.Errors) != 2 {
+		t.Errorf("The result was incorrect: %+v", res)
+	}
+	if len(res.Errors) == 2 && (res.Errors[0].Row != 2 || res.Errors[1].Row != 3) {
+		t.Error("The errors should have been reported with their row")
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 6 || lines[1] != "1,10.00,usd,8.00," || lines[4] != "4,1,usd,0.80," {
+		t.Error("The rows should have been written: " + out.String())
+	}
+}
+
+func TestConvert_CSVNoDecimalPlaces(t *testing.T) {
+	opts := Options{
+		AmountField:   "amount",
+		CurrencyField: "currency",
+		Target:        "xau",
+		Rounding:      trader.RoundHalfEven,
+	}
+	in := "amount,currency\n123456,usd\n"
+
+	var out bytes.Buffer
+	if _, err := Convert(getTrader(), strings.NewReader(in), &out, opts); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if out.String() != "amount,currency,converted,error\n123456,usd,61.728,\n" {
+		t.Error("The row was incorrectly converted: " + out.String())
+	}
+}
+
+func TestConvert_JSONL(t *testing.T) {
+	opts := Options{
+		Format:        JSONL,
+		AmountField:   "amount",
+		CurrencyField: "currency",
+		Target:        "usd",
+		OutputField:   "amount_usd",
+	}
+	in := `{"id":1,"amount":"8","currency":"eur"}
+
+{"id":2,"amount":12.5,"currency":"usd"}
+{"id":3
+{"id":4,"amount":"1","currency":"gel"}
+`
+
+	var out bytes.Buffer
+	res, err := Convert(getTrader(), strings.NewReader(in), &out, opts)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if res.Rows != 4 || res.Converted != 2 || len(res.Errors) != 2 {
+		t.Errorf("The result was incorrect: %+v", res)
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	if lines[0] != `{"amount":"8","amount_usd":"10.00","currency":"eur","id":1}` {
+		t.Error("The row was incorrectly converted: " + lines[0])
+	}
+	if lines[1] != `{"amount":12.5,"amount_usd":"12.50","currency":"usd","id":2}` {
+		t.Error("The row was incorrectly converted: " + lines[1])
+	}
+	if !strings.Contains(lines[2], `"error"`) || !strings.Contains(lines[3], `"error"`) {
+		t.Error("The row errors should have been reported")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"gopkg.in/processout/trader.v2"
+	"gopkg.in/processout/trader.v2/batch"
+	"gopkg.in/processout/trader.v2/internal/ratefile"
+)
+
+// batchFlags are the flags of the batch command
+type batchFlags struct {
+	to       string
+	amount   string
+	currency string
+	rounding string
+	format   string
+	output   string
+}
+
+// register registers the batch flags in the given FlagSet
+func (f *batchFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.to, "to", "", "currency the amounts are converted to")
+	fs.StringVar(&f.amount, "amount", "amount", "name of the amount column")
+	fs.StringVar(&f.currency, "currency", "currency", "name of the currency column")
+	fs.StringVar(&f.rounding, "rounding", trader.RoundHalfUp.String(),
+		"rounding mode: half-up, half-even, down, up, floor or ceil")
+	fs.StringVar(&f.format, "format", "csv", "format of the rows: csv or jsonl")
+	fs.StringVar(&f.output, "output", "converted", "name of the converted amount column")
+}
+
+// convertBatch converts the rows read from stdin, and writes them to stdout.
+// The summary of the conversion and the errors of the rows are written to
+// stderr
+func (c command) convertBatch() error {
+	if c.batch.to == "" {
+		return errors.New("the -to flag is required")
+	}
+	format, err := batch.ParseFormat(c.batch.format)
+	if err != nil {
+		return err
+	}
+	rounding, err := trader.ParseRoundingMode(c.batch.rounding)
+	if err != nil {
+		return err
+	}
+
+	t, err := ratefile.Load(c.rates)
+	if err != nil {
+		return err
+	}
+
+	res, err := batch.Convert(t, c.stdin, c.stdout, batch.Options{
+		Format:        format,
+		AmountField:   c.batch.amount,
+		CurrencyField: c.batch.currency,
+		Target:        trader.CurrencyCode(c.batch.to),
+		Rounding:      rounding,
+		OutputField:   c.batch.output,
+	})
+	for _, e := range res.Errors {
+		fmt.Fprintln(c.stderr, e)
+	}
+	fmt.Fprintf(c.stderr, "%d rows, %d converted, %d errors\n",
+		res.Rows, res.Converted, len(res.Errors))
+
+	return err
+}
@@ -0,0 +1,223 @@
+// Command trader converts amounts and prints rates and currency information
+// from a rates file, without writing any Go.
+//
+// Usage:
+//
+//	trader [-rates file] [-json] convert <amount> <from> <to>
+//	trader [-rates file] [-json] rate <from> <to>
+//	trader [-json] info <code>
+//	trader [-rates file] [-json] list
+//	trader [-rates file] batch -to <code> [-amount column] [-currency column]
+//		[-rounding mode] [-format csv|jsonl] < rows > converted
+//
+// The rates file is a JSON or CSV file, as read by the internal ratefile
+// package. It defaults to the TRADER_RATES environment variable, or to
+// rates.json.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+	"gopkg.in/processout/trader.v2/internal/ratefile"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "trader:", err)
+		os.Exit(1)
+	}
+}
+
+// command is a command of the CLI, with its flags
+type command struct {
+	rates  string
+	json   bool
+	batch  batchFlags
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// run runs the CLI with the given arguments, reading its input from stdin
+// and writing its output to stdout and its diagnostics to stderr
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	rates := os.Getenv("TRADER_RATES")
+	if rates == "" {
+		rates = "rates.json"
+	}
+
+	c := command{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+	fs := flag.NewFlagSet("trader", flag.ContinueOnError)
+	fs.StringVar(&c.rates, "rates", rates, "path to the JSON or CSV rates file")
+	fs.BoolVar(&c.json, "json", false, "print machine-readable JSON output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	args = fs.Args()
+	if len(args) == 0 {
+		return errors.New("a command is required: convert, rate, info, list or batch")
+	}
+	// Flags are also accepted right after the command name
+	if args[0] == "batch" {
+		c.batch.register(fs)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	name, args := args[0], fs.Args()
+
+	switch name {
+	case "convert":
+		if len(args) != 3 {
+			return errors.New("usage: trader convert <amount> <from> <to>")
+		}
+		return c.convert(args[0], trader.CurrencyCode(args[1]), trader.CurrencyCode(args[2]))
+	case "rate":
+		if len(args) != 2 {
+			return errors.New("usage: trader rate <from> <to>")
+		}
+		return c.rate(trader.CurrencyCode(args[0]), trader.CurrencyCode(args[1]))
+	case "info":
+		if len(args) != 1 {
+			return errors.New("usage: trader info <code>")
+		}
+		return c.info(trader.CurrencyCode(args[0]))
+	case "list":
+		if len(args) != 0 {
+			return errors.New("usage: trader list")
+		}
+		return c.list()
+	case "batch":
+		if len(args) != 0 {
+			return errors.New("usage: trader batch -to <code> < rows > converted")
+		}
+		return c.convertBatch()
+	}
+
+	return fmt.Errorf("unknown command %q", name)
+}
+
+// print prints v as JSON if the json flag was set, or the given text
+// otherwise
+func (c command) print(v interface{}, format string, args ...interface{}) error {
+	if c.json {
+		e := json.NewEncoder(c.stdout)
+		e.SetIndent("", "  ")
+		return e.Encode(v)
+	}
+
+	_, err := fmt.Fprintf(c.stdout, format+"\n", args...)
+	return err
+}
+
+// convert converts the given amount from a currency to another
+func (c command) convert(amount string, from, to trader.CurrencyCode) error {
+	t, err := ratefile.Load(c.rates)
+	if err != nil {
+		return err
+	}
+
+	a, err := t.NewAmountFromString(amount, from)
+	if err != nil {
+		return err
+	}
+	n, conv, err := a.ConvertWithTrace(to)
+	if err != nil {
+		return err
+	}
+
+	return c.print(conv, "%s %s = %s %s (rate %s)",
+		value(a), a.Currency.Code, value(n), n.Currency.Code, conv.Rate)
+}
+
+// value returns the value of the given amount with the decimal places of its
+// currency, or as is if the currency has none, e.g. XAU
+func value(a trader.Amount) string {
+	places := a.Currency.DecimalPlaces()
+	if places < 0 {
+		return a.Value.String()
+	}
+
+	return a.String(int32(places))
+}
+
+// rate prints the rate from a currency to another
+func (c command) rate(from, to trader.CurrencyCode) error {
+	t, err := ratefile.Load(c.rates)
+	if err != nil {
+		return err
+	}
+
+	a, err := t.NewAmount(decimal.New(1, 0), from)
+	if err != nil {
+		return err
+	}
+	rate, err := a.RateTo(to)
+	if err != nil {
+		return err
+	}
+
+	return c.print(struct {
+		From string          `json:"from"`
+		To   string          `json:"to"`
+		Rate decimal.Decimal `json:"rate"`
+	}{a.Currency.Code.String(), to.String(), rate},
+		"1 %s = %s %s", a.Currency.Code, rate, to)
+}
+
+// info prints the ISO 4217 information of a currency. It doesn't need any
+// rates file
+func (c command) info(code trader.CurrencyCode) error {
+	i := code.Information()
+	if i == nil {
+		return fmt.Errorf("Currency `%s' does not exist", code)
+	}
+
+	return c.print(struct {
+		Code      string   `json:"code"`
+		Number    uint     `json:"number"`
+		Places    int      `json:"places"`
+		FullName  string   `json:"full_name"`
+		Countries []string `json:"countries"`
+	}{code.String(), i.Number, i.Places, i.FullName, i.Countries},
+		"%s (%03d): %s\nDecimal places: %d\nCountries: %s",
+		code, i.Number, i.FullName, i.Places, strings.Join(i.Countries, ", "))
+}
+
+// list prints the currencies of the rates file, with their value relative
+// to the base currency
+func (c command) list() error {
+	t, err := ratefile.Load(c.rates)
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		return c.print(t, "")
+	}
+
+	for _, cur := range t.Currencies {
+		base := ""
+		if t.BaseCurrency.Is(cur.Code) {
+			base = " (base)"
+		}
+		if err := c.print(nil, "%s\t%s%s", cur.Code, cur.Value, base); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
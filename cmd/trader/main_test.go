@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRates(t *testing.T) (string, func()) {
+	dir, err := os.MkdirTemp("", "trader")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "rates.csv")
+	if err := os.WriteFile(path, []byte("usd,1\neur,0.8\njpy,100\nxau,0.0005\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestRun(t *testing.T) {
+	path, clean := writeRates(t)
+	defer clean()
+
+	var out bytes.Buffer
+	if err := run([]string{"-rates", path}, nil, &out, io.Discard); err == nil {
+		t.Error("There should have been an error")
+	}
+	if err := run([]string{"-rates", path, "lel"}, nil, &out, io.Discard); err == nil {
+		t.Error("There should have been an error")
+	}
+	if err := run([]string{"-rates", path, "convert", "12.50"}, nil, &out, io.Discard); err == nil {
+		t.Error("There should have been an error")
+	}
+	if err := run([]string{"-rates", path, "convert", "12.50", "usd", "gel"}, nil, &out, io.Discard); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	out.Reset()
+	if err := run([]string{"-rates", path, "convert", "12.50", "usd", "eur"}, nil, &out, io.Discard); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if out.String() != "12.50 USD = 10.00 EUR (rate 0.8)\n" {
+		t.Error("The conversion was incorrectly printed: " + out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"-rates", path, "convert", "61.728", "xau", "usd"}, nil, &out, io.Discard); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if out.String() != "61.728 XAU = 123456.00 USD (rate 2000)\n" {
+		t.Error("The conversion was incorrectly printed: " + out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"-rates", path, "convert", "123456", "usd", "xau"}, nil, &out, io.Discard); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if out.String() != "123456.00 USD = 61.728 XAU (rate 0.0005)\n" {
+		t.Error("The conversion was incorrectly printed: " + out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"-rates", path, "rate", "eur", "jpy"}, nil, &out, io.Discard); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if out.String() != "1 EUR = 125 JPY\n" {
+		t.Error("The rate was incorrectly printed: " + out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"info", "xof"}, nil, &out, io.Discard); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !strings.HasPrefix(out.String(), "XOF (952): CFA franc BCEAO") {
+		t.Error("The information was incorrectly printed: " + out.String())
+	}
+	if err := run([]string{"info", "lel"}, nil, &out, io.Discard); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	out.Reset()
+	if err := run([]string{"-rates", path, "list"}, nil, &out, io.Discard); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if out.String() != "USD\t1 (base)\nEUR\t0.8\nJPY\t100\nXAU\t0.0005\n" {
+		t.Error("The currencies were incorrectly printed: " + out.String())
+	}
+}
+
+func TestRun_JSON(t *testing.T) {
+	path, clean := writeRates(t)
+	defer clean()
+
+	var out bytes.Buffer
+	err := run([]string{"-rates", path, "convert", "-json", "12.50", "usd", "eur"}, nil, &out, io.Discard)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &m); err != nil {
+		t.Error("The output should have been JSON: " + out.String())
+	}
+	if _, ok := m["rate"]; !ok {
+		t.Error("The conversion record should have been printed")
+	}
+
+	out.Reset()
+	if err := run([]string{"-json", "info", "usd"}, nil, &out, io.Discard); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if err := json.Unmarshal(out.Bytes(), &m); err != nil || m["number"] != float64(840) {
+		t.Error("The information was incorrectly printed: " + out.String())
+	}
+}
+
+func TestRun_Batch(t *testing.T) {
+	path, clean := writeRates(t)
+	defer clean()
+
+	in := strings.NewReader("amount,currency\n10,usd\n1,gel\n")
+	var out, errs bytes.Buffer
+	if err := run([]string{"-rates", path, "batch"}, in, &out, &errs); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	err := run([]string{"-rates", path, "batch", "-to", "eur", "-rounding", "down"},
+		in, &out, &errs)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !strings.HasPrefix(out.String(), "amount,currency,converted,error\n10,usd,8.00,\n") {
+		t.Error("The rows were incorrectly converted: " + out.String())
+	}
+	if !strings.HasSuffix(errs.String(), "2 rows, 1 converted, 1 errors\n") {
+		t.Error("The summary was incorrectly printed: " + errs.String())
+	}
+}
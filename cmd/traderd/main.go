@@ -0,0 +1,58 @@
+// Command traderd serves the conversions of a rates file over HTTP, see the
+// server package for the endpoints. The rates file is reloaded whenever it
+// changes, without restarting.
+//
+// Usage:
+//
+//	traderd [-addr :8080] [-rates rates.json] [-interval 5s]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"gopkg.in/processout/trader.v2/internal/ratefile"
+	"gopkg.in/processout/trader.v2/server"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	rates := flag.String("rates", "rates.json", "path to the JSON or CSV rates file")
+	interval := flag.Duration("interval", 5*time.Second, "interval between two checks of the rates file")
+	flag.Parse()
+
+	t, err := ratefile.Load(*rates)
+	if err != nil {
+		log.Fatalf("traderd: could not load the rates: %s", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	h := server.NewHandler(t)
+	go h.WatchFile(ctx, *rates, ratefile.Load, *interval, func(err error) {
+		log.Printf("traderd: could not reload the rates: %s", err)
+	})
+
+	srv := &http.Server{
+		Addr:    *addr,
+		Handler: h,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	log.Printf("traderd: listening on %s", *addr)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatalf("traderd: %s", err)
+	}
+}
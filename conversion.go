@@ -0,0 +1,94 @@
+package trader
+
+import (
+	"time"
+
+	"github.com/processout/decimal"
+)
+
+// Conversion is the record of a currency conversion. It contains everything
+// needed to prove which rate was applied to an Amount, and can be serialized
+// to JSON to be kept in an audit log
+type Conversion struct {
+	// Source is the Amount that was converted
+	Source Amount `json:"source"`
+	// Target is the result of the conversion
+	Target Amount `json:"target"`
+	// Rate is the rate applied to the Source value to get the Target value
+	Rate decimal.Decimal `json:"rate"`
+	// InverseRate is the rate converting the Target back to the Source
+	// currency
+	InverseRate decimal.Decimal `json:"inverse_rate"`
+	// BaseCurrency is the base currency of the Trader used for the conversion
+	BaseCurrency CurrencyCode `json:"base_currency"`
+	// SnapshotID identifies the set of rates used, if known by the Trader
+	SnapshotID string `json:"snapshot_id,omitempty"`
+	// SnapshotTime is the time at which the rates were fetched, if known by
+	// the Trader
+	SnapshotTime *time.Time `json:"snapshot_time,omitempty"`
+	// Unrounded is the Target value before any rounding was applied
+	Unrounded decimal.Decimal `json:"unrounded"`
+	// Rounding is the adjustment applied to Unrounded to get the Target
+	// value. It is zero if no rounding happened
+	Rounding decimal.Decimal `json:"rounding"`
+	// Path is the list of currencies the conversion went through, as every
+	// cross rate is computed through the base currency
+	Path []CurrencyCode `json:"path"`
+}
+
+// ConvertWithTrace converts the Amount to the given Currency, exactly like
+// ToCurrency does, but also returns the Conversion record describing how
+// the converted Amount was computed
+func (a Amount) ConvertWithTrace(code CurrencyCode) (Amount, Conversion, error) {
+	rate, err := a.RateTo(code)
+	if err != nil {
+		return emptyAmount, Conversion{}, err
+	}
+
+	n := a
+	unrounded := a.Value
+	if !a.Currency.Is(code) {
+		unrounded, err = a.trader().arithmetic().Mul(a.Value, rate)
+		if err != nil {
+			return emptyAmount, Conversion{}, err
+		}
+		n, err = a.trader().NewAmount(a.trader().resultScale.round(unrounded), code)
+		if err != nil {
+			return emptyAmount, Conversion{}, err
+		}
+	}
+
+	inverse, err := n.RateTo(a.Currency.Code)
+	if err != nil {
+		return emptyAmount, Conversion{}, err
+	}
+
+	return n, Conversion{
+		Source:       a,
+		Target:       n,
+		Rate:         rate,
+		InverseRate:  inverse,
+		BaseCurrency: a.trader().BaseCurrency.Code,
+		SnapshotID:   a.trader().SnapshotID,
+		SnapshotTime: a.trader().SnapshotTime,
+		Unrounded:    unrounded,
+		Rounding:     n.Value.Sub(unrounded),
+		Path:         a.conversionPath(n.Currency.Code),
+	}, nil
+}
+
+// conversionPath returns the list of currencies a conversion from the
+// currency of a to the given code goes through
+func (a Amount) conversionPath(code CurrencyCode) []CurrencyCode {
+	from := a.Currency.Code
+	if a.Currency.Is(code) {
+		return []CurrencyCode{from}
+	}
+
+	base := a.trader().BaseCurrency
+	if a.Currency.Is(base.Code) || base.Is(code) || base.Code == "" {
+		return []CurrencyCode{from, code}
+	}
+
+	return []CurrencyCode{from, base.Code, code}
+}
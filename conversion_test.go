@@ -0,0 +1,138 @@
+package trader
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/processout/decimal"
+)
+
+func getTrader3() Trader {
+	c1, _ := NewCurrency("USD", decimal.NewFromFloat(1))
+	c2, _ := NewCurrency("EUR", decimal.NewFromFloat(0.8))
+	c3, _ := NewCurrency("GBP", decimal.NewFromFloat(0.5))
+	trader, _ := New(Currencies{c1, c2, c3}, "usd")
+	trader.SnapshotID = "snapshot-1"
+	snapshotTime := time.Date(2016, 9, 23, 15, 0, 0, 0, time.UTC)
+	trader.SnapshotTime = &snapshotTime
+	return trader
+}
+
+func TestAmount_ConvertWithTrace(t *testing.T) {
+	trader := getTrader3()
+	amount, _ := trader.NewAmountFromString("2", "eur")
+
+	_, _, err := amount.ConvertWithTrace("gel")
+	if err == nil {
+		t.Error("There should have been an error")
+	}
+
+	n, c, err := amount.ConvertWithTrace("gbp")
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	expected, _ := amount.ToCurrency("gbp")
+	if !n.Value.Equals(expected.Value) || !c.Target.Value.Equals(expected.Value) {
+		t.Error("The amount was wrongly converted: " + n.String(3))
+	}
+	if c.Rate.StringFixed(3) != "0.625" {
+		t.Error("The rate was wrongly recorded: " + c.Rate.String())
+	}
+	if c.InverseRate.StringFixed(3) != "1.600" {
+		t.Error("The inverse rate was wrongly recorded: " + c.InverseRate.String())
+	}
+	if c.BaseCurrency != "USD" {
+		t.Error("The base currency was wrongly recorded")
+	}
+	if c.SnapshotID != "snapshot-1" || c.SnapshotTime != trader.SnapshotTime {
+		t.Error("The snapshot was wrongly recorded")
+	}
+	if !c.Rounding.Equals(decimal.New(0, 0)) {
+		t.Error("No rounding should have been recorded")
+	}
+	if len(c.Path) != 3 || c.Path[0] != "EUR" || c.Path[1] != "USD" || c.Path[2] != "GBP" {
+		t.Errorf("The conversion path was wrong: %v", c.Path)
+	}
+
+	_, c, err = amount.ConvertWithTrace("usd")
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if len(c.Path) != 2 || c.Path[0] != "EUR" || c.Path[1] != "USD" {
+		t.Errorf("The conversion path was wrong: %v", c.Path)
+	}
+
+	n, c, err = amount.ConvertWithTrace("eur")
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if n.Value != amount.Value {
+		t.Error("Amount shouldn't have changed")
+	}
+	if c.Rate.StringFixed(1) != "1.0" || len(c.Path) != 1 {
+		t.Error("The conversion should have been recorded as an identity")
+	}
+}
+
+func TestConversion_JSON(t *testing.T) {
+	trader := getTrader3()
+	amount, _ := trader.NewAmountFromString("2", "usd")
+	_, c, _ := amount.ConvertWithTrace("eur")
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	for _, k := range []string{"source", "target", "rate", "inverse_rate",
+		"base_currency", "snapshot_id", "snapshot_time", "unrounded",
+		"rounding", "path"} {
+
+		if _, ok := m[k]; !ok {
+			t.Error("The key " + k + " was missing from the record")
+		}
+	}
+}
+
+func TestConversion_JSONUnknownSnapshot(t *testing.T) {
+	trader := getTrader3()
+	trader.SnapshotTime = nil
+	amount, _ := trader.NewAmountFromString("2", "usd")
+	_, c, _ := amount.ConvertWithTrace("eur")
+
+	for _, v := range []interface{}{trader, c} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Error("There shouldn't have been an error")
+		}
+
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Error("There shouldn't have been an error")
+		}
+		if _, ok := m["snapshot_time"]; ok {
+			t.Error("The unknown snapshot time shouldn't have been written")
+		}
+	}
+}
+
+func TestAmount_ConvertWithTraceRounding(t *testing.T) {
+	trader := getTrader3().WithResultScale(2, RoundDown)
+	amount, _ := trader.NewAmountFromString("1.03125", "usd")
+
+	n, c, err := amount.ConvertWithTrace("eur")
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if n.Value.String() != "0.82" {
+		t.Error("The converted value should have been rounded: " + n.Value.String())
+	}
+	if c.Unrounded.String() != "0.825" || c.Rounding.String() != "-0.005" {
+		t.Error("The rounding was incorrectly recorded: " + c.Rounding.String())
+	}
+}
@@ -1,7 +1,6 @@
 package trader
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 
@@ -37,7 +36,7 @@ var emptyCurrency = Currency{}
 func NewCurrency(code CurrencyCode, v decimal.Decimal) (Currency, error) {
 	// Verify code:
 	if ok := code.Verify(); !ok {
-		return emptyCurrency, errors.New("Currency `" + code.String() + "' does not exist")
+		return emptyCurrency, UnknownCurrencyError{code}
 	}
 	return Currency{
 		Code:  code.format(),
@@ -57,7 +56,30 @@ func (c Currencies) Find(code CurrencyCode) (Currency, error) {
 		}
 	}
 
-	return emptyCurrency, fmt.Errorf("The currency code %s could not be found.", code)
+	return emptyCurrency, CurrencyNotFoundError{code}
+}
+
+// UnknownCurrencyError is the error returned when a currency code is not
+// part of ISO 4217
+type UnknownCurrencyError struct {
+	Code CurrencyCode
+}
+
+// Error to implement error interface
+func (e UnknownCurrencyError) Error() string {
+	return "Currency `" + e.Code.String() + "' does not exist"
+}
+
+// CurrencyNotFoundError is the error returned when a currency code could not
+// be found in a Currencies slice, e.g. when the currency is not supported by
+// a Trader
+type CurrencyNotFoundError struct {
+	Code CurrencyCode
+}
+
+// Error to implement error interface
+func (e CurrencyNotFoundError) Error() string {
+	return fmt.Sprintf("The currency code %s could not be found.", e.Code)
 }
 
 // Is returns true if the given code is the code of the Currency, false
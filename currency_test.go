@@ -95,3 +95,21 @@ func TestCurrency_DecimalPlaces(t *testing.T) {
 		t.Error("The decimal places should have been 4")
 	}
 }
+
+func TestCurrencyErrors(t *testing.T) {
+	_, err := NewCurrency("lel", decimal.Decimal{})
+	if _, ok := err.(UnknownCurrencyError); !ok {
+		t.Error("The error should have been an UnknownCurrencyError")
+	}
+	if err.Error() != "Currency `LEL' does not exist" {
+		t.Error("The error message was incorrect: " + err.Error())
+	}
+
+	_, err = Currencies{}.Find("usd")
+	if e, ok := err.(CurrencyNotFoundError); !ok || e.Code != "usd" {
+		t.Error("The error should have been a CurrencyNotFoundError")
+	}
+	if err.Error() != "The currency code USD could not be found." {
+		t.Error("The error message was incorrect: " + err.Error())
+	}
+}
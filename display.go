@@ -0,0 +1,222 @@
+package trader
+
+import (
+	"strings"
+
+	"github.com/processout/decimal"
+)
+
+// symbols are the symbols of the most common currencies
+var symbols = map[CurrencyCode]string{
+	"BRL": "R$",
+	"CHF": "CHF",
+	"CNY": "¥",
+	"EUR": "€",
+	"GBP": "£",
+	"ILS": "₪",
+	"INR": "₹",
+	"JPY": "¥",
+	"KRW": "₩",
+	"NGN": "₦",
+	"PHP": "₱",
+	"RUB": "₽",
+	"THB": "฿",
+	"TRY": "₺",
+	"UAH": "₴",
+	"USD": "$",
+	"VND": "₫",
+}
+
+// Symbol returns the symbol of the currency, e.g. € for EUR, or its code if
+// it has no well-known symbol
+func (c Currency) Symbol() string {
+	if s, ok := symbols[c.Code.format()]; ok {
+		return s
+	}
+
+	return c.Code.String()
+}
+
+// CompactSuffix is a suffix used by the compact notation for the values
+// greater than or equal to Threshold, which are divided by Threshold
+type CompactSuffix struct {
+	Threshold decimal.Decimal
+	Suffix    string
+}
+
+// CompactFormat configures the compact notation of amounts, e.g. "1.2K USD"
+type CompactFormat struct {
+	// Suffixes are the suffixes of the notation, sorted by increasing
+	// threshold
+	Suffixes []CompactSuffix
+	// Places is the maximum number of decimal places of the compact value
+	Places int32
+	// Symbol is set to use the symbol of the currency rather than its code
+	Symbol bool
+}
+
+// DefaultCompactFormat is the default compact notation, using K, M, B and
+// T for thousands, millions, billions and trillions
+var DefaultCompactFormat = CompactFormat{
+	Suffixes: []CompactSuffix{
+		{decimal.New(1, 3), "K"},
+		{decimal.New(1, 6), "M"},
+		{decimal.New(1, 9), "B"},
+		{decimal.New(1, 12), "T"},
+	},
+	Places: 1,
+}
+
+// Compact returns the Amount in the given compact notation, e.g. "1.2K USD"
+// or "3.4M €". The values below the first threshold are written with the
+// decimal places of the currency, or of the value if the currency has none
+func (a Amount) Compact(f CompactFormat) string {
+	unit := a.Currency.Code.String()
+	if f.Symbol {
+		unit = a.Currency.Symbol()
+	}
+
+	v := a.Value
+	neg := v.Cmp(decimal.New(0, 0)) < 0
+	if neg {
+		v = decimal.New(0, 0).Sub(v)
+	}
+
+	places := a.displayPlaces()
+	s := RoundHalfUp.Round(v, places).StringFixed(places)
+	for i := len(f.Suffixes) - 1; i >= 0; i-- {
+		suffix := f.Suffixes[i]
+		if v.Cmp(suffix.Threshold) < 0 {
+			continue
+		}
+
+		c := RoundHalfUp.Round(v.Div(suffix.Threshold), f.Places)
+		// The rounding may reach the next threshold, e.g. 999.96K
+		if i+1 < len(f.Suffixes) &&
+			c.Mul(suffix.Threshold).Cmp(f.Suffixes[i+1].Threshold) >= 0 {
+
+			suffix = f.Suffixes[i+1]
+			c = RoundHalfUp.Round(v.Div(suffix.Threshold), f.Places)
+		}
+		s = c.String() + suffix.Suffix
+		break
+	}
+
+	if neg {
+		s = "-" + s
+	}
+	return s + " " + unit
+}
+
+// Accounting returns the value of the Amount with the decimal places of its
+// currency, or of the value if the currency has none, and its thousands
+// grouped, negative values being written in parentheses, e.g. "(1,234.00)"
+func (a Amount) Accounting() string {
+	places := a.displayPlaces()
+	v := RoundHalfUp.Round(a.Value, places)
+	if v.Cmp(decimal.New(0, 0)) < 0 {
+		return "(" + group(decimal.New(0, 0).Sub(v).StringFixed(places)) + ")"
+	}
+
+	return group(v.StringFixed(places))
+}
+
+// displayPlaces returns the decimal places of the currency of the Amount,
+// or those of its value if the currency has none, e.g. XAU
+func (a Amount) displayPlaces() int32 {
+	if places := a.places(); places >= 0 {
+		return places
+	}
+	if exp := a.Value.Exponent(); exp < 0 {
+		return -exp
+	}
+
+	return 0
+}
+
+// group groups the thousands of the integer part of the given unsigned
+// decimal string with commas
+func group(s string) string {
+	i := strings.IndexByte(s, '.')
+	if i < 0 {
+		i = len(s)
+	}
+
+	var b strings.Builder
+	for j := 0; j < i; j++ {
+		if j > 0 && (i-j)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte(s[j])
+	}
+	b.WriteString(s[i:])
+	return b.String()
+}
+
+// TableOptions configure the rendering of amounts in a column
+type TableOptions struct {
+	// Accounting is set to write negative values in parentheses, see
+	// Amount.Accounting. Otherwise, thousands are grouped and negative
+	// values are prefixed with a minus sign
+	Accounting bool
+	// Code is set to append the currency code to each value
+	Code bool
+	// Width is the minimum width of the lines
+	Width int
+}
+
+// Table renders the given amounts as lines of the same width, right-aligned
+// on their decimal point, to be printed as a column of a statement
+func Table(amounts []Amount, opts TableOptions) []string {
+	ints := make([]string, len(amounts))
+	fracs := make([]string, len(amounts))
+	var intWidth, fracWidth int
+	for i, a := range amounts {
+		s := a.Accounting()
+		if !opts.Accounting && strings.HasPrefix(s, "(") {
+			s = "-" + strings.Trim(s, "()")
+		} else if opts.Accounting && !strings.HasPrefix(s, "(") {
+			// Keeps the room of the closing parenthesis
+			s += " "
+		}
+
+		ints[i], fracs[i] = s, ""
+		if j := strings.IndexByte(s, '.'); j >= 0 {
+			ints[i], fracs[i] = s[:j], s[j:]
+		} else if opts.Accounting {
+			ints[i], fracs[i] = s[:len(s)-1], s[len(s)-1:]
+		}
+
+		intWidth = maxInt(intWidth, runeCount(ints[i]))
+		fracWidth = maxInt(fracWidth, runeCount(fracs[i]))
+	}
+
+	lines := make([]string, len(amounts))
+	for i, a := range amounts {
+		line := strings.Repeat(" ", intWidth-runeCount(ints[i])) + ints[i] +
+			fracs[i] + strings.Repeat(" ", fracWidth-runeCount(fracs[i]))
+		if opts.Code {
+			line += " " + a.Currency.Code.String()
+		}
+		if n := runeCount(line); n < opts.Width {
+			line = strings.Repeat(" ", opts.Width-n) + line
+		}
+		lines[i] = line
+	}
+
+	return lines
+}
+
+// runeCount returns the number of runes of s
+func runeCount(s string) int {
+	return len([]rune(s))
+}
+
+// maxInt returns the greatest of x and y
+func maxInt(x, y int) int {
+	if x > y {
+		return x
+	}
+
+	return y
+}
@@ -0,0 +1,116 @@
+package trader
+
+import (
+	"testing"
+
+	"github.com/processout/decimal"
+)
+
+func TestCurrency_Symbol(t *testing.T) {
+	eur, _ := NewCurrency("eur", decimal.New(1, 0))
+	sek, _ := NewCurrency("sek", decimal.New(1, 0))
+
+	if eur.Symbol() != "€" || sek.Symbol() != "SEK" {
+		t.Error("The symbols were incorrectly returned")
+	}
+}
+
+func TestAmount_Compact(t *testing.T) {
+	trader := getWordsTrader()
+	tests := []struct {
+		value    string
+		code     CurrencyCode
+		format   CompactFormat
+		expected string
+	}{
+		{"1234", "usd", DefaultCompactFormat, "1.2K USD"},
+		{"1000", "usd", DefaultCompactFormat, "1K USD"},
+		{"999.5", "usd", DefaultCompactFormat, "999.50 USD"},
+		{"-1250", "usd", DefaultCompactFormat, "-1.3K USD"},
+		{"3400000", "eur", CompactFormat{Suffixes: DefaultCompactFormat.Suffixes, Places: 1, Symbol: true}, "3.4M €"},
+		{"999960", "usd", DefaultCompactFormat, "1M USD"},
+		{"123.456", "xau", DefaultCompactFormat, "123.456 XAU"},
+		{"12345678901234", "jpy", DefaultCompactFormat, "12.3T JPY"},
+		{"1234567", "usd", CompactFormat{Suffixes: DefaultCompactFormat.Suffixes, Places: 3}, "1.235M USD"},
+		{"150000", "inr", CompactFormat{
+			Suffixes: []CompactSuffix{{decimal.New(1, 5), " lakh"}, {decimal.New(1, 7), " crore"}},
+			Places:   2,
+			Symbol:   true,
+		}, "1.5 lakh ₹"},
+	}
+
+	for _, test := range tests {
+		a, _ := trader.NewAmountFromString(test.value, test.code)
+		if s := a.Compact(test.format); s != test.expected {
+			t.Errorf("%s %s should have been %q, got %q", test.value, test.code, test.expected, s)
+		}
+	}
+}
+
+func TestAmount_Accounting(t *testing.T) {
+	trader := getWordsTrader()
+	tests := []struct {
+		value    string
+		code     CurrencyCode
+		expected string
+	}{
+		{"1234", "usd", "1,234.00"},
+		{"-1234", "usd", "(1,234.00)"},
+		{"-0.005", "usd", "(0.01)"},
+		{"123", "usd", "123.00"},
+		{"1234567.891", "usd", "1,234,567.89"},
+		{"-1234567", "jpy", "(1,234,567)"},
+		{"1234.56", "xau", "1,234.56"},
+		{"-1234", "xau", "(1,234)"},
+	}
+
+	for _, test := range tests {
+		a, _ := trader.NewAmountFromString(test.value, test.code)
+		if s := a.Accounting(); s != test.expected {
+			t.Errorf("%s %s should have been %q, got %q", test.value, test.code, test.expected, s)
+		}
+	}
+}
+
+func TestTable(t *testing.T) {
+	trader := getWordsTrader()
+	var amounts []Amount
+	for _, v := range []struct {
+		value string
+		code  CurrencyCode
+	}{{"1234.5", "usd"}, {"-12", "usd"}, {"-98765", "jpy"}, {"0.125", "kwd"}} {
+		a, _ := trader.NewAmountFromString(v.value, v.code)
+		amounts = append(amounts, a)
+	}
+
+	expected := []string{
+		"  1,234.50  USD",
+		"    -12.00  USD",
+		"-98,765     JPY",
+		"      0.125 KWD",
+	}
+	lines := Table(amounts, TableOptions{Code: true})
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Line %d should have been %q, got %q", i, expected[i], lines[i])
+		}
+	}
+
+	expected = []string{
+		"  1,234.50  ",
+		"    (12.00) ",
+		"(98,765)    ",
+		"      0.125 ",
+	}
+	lines = Table(amounts, TableOptions{Accounting: true, Width: 10})
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Line %d should have been %q, got %q", i, expected[i], lines[i])
+		}
+	}
+
+	lines = Table(amounts[:1], TableOptions{Width: 12})
+	if lines[0] != "    1,234.50" {
+		t.Errorf("The line should have been padded to the width: %q", lines[0])
+	}
+}
@@ -0,0 +1,149 @@
+package trader
+
+import (
+	"math"
+
+	"github.com/processout/decimal"
+)
+
+// maxFixedShift is the greatest power of ten a fixedPoint may be scaled by
+// to align it with another one
+const maxFixedShift = 18
+
+// fixedPoint is a fixed-point representation of the value of an Amount,
+// equal to units * 10^exp. It is used to add, substract and compare amounts
+// in the same currency without any allocation. It is only valid while of
+// is the value of the Amount: if the Value of the Amount is replaced, the
+// operations fall back to the decimal package
+type fixedPoint struct {
+	of    decimal.Decimal
+	units int64
+	exp   int32
+	ok    bool
+}
+
+// newFixedPoint returns the fixed-point representation of d. It is invalid
+// if the digits of d don't fit in an int64
+func newFixedPoint(d decimal.Decimal) fixedPoint {
+	// d times 10^-exp is an integer, the digits of d
+	c := d.Mul(decimal.New(1, -d.Exponent())).Rat().Num()
+	if !c.IsInt64() {
+		return fixedPoint{}
+	}
+
+	return fixedPoint{
+		of:    d,
+		units: c.Int64(),
+		exp:   d.Exponent(),
+		ok:    true,
+	}
+}
+
+// valid returns true if f is the fixed-point representation of d
+func (f fixedPoint) valid(d decimal.Decimal) bool {
+	return f.ok && f.of == d
+}
+
+// align returns the units of f and g scaled to the same exponent, and that
+// exponent. It returns false if the units would overflow
+func (f fixedPoint) align(g fixedPoint) (int64, int64, int32, bool) {
+	if f.exp == g.exp {
+		return f.units, g.units, f.exp, true
+	}
+	if f.exp < g.exp {
+		u, ok := shift(g.units, g.exp-f.exp)
+		return f.units, u, f.exp, ok
+	}
+
+	u, ok := shift(f.units, f.exp-g.exp)
+	return u, g.units, g.exp, ok
+}
+
+// shift returns n * 10^places. It returns false if the result would
+// overflow
+func shift(n int64, places int32) (int64, bool) {
+	if places > maxFixedShift {
+		return 0, false
+	}
+
+	for i := int32(0); i < places; i++ {
+		if n > math.MaxInt64/10 || n < math.MinInt64/10 {
+			return 0, false
+		}
+		n *= 10
+	}
+
+	return n, true
+}
+
+// fastPath returns the fixed-point representations of a and b if they can
+// be added, substracted or compared directly: if they have the same Trader,
+// using the default backend, and are in the same currency
+func (a Amount) fastPath(b Amount) (fixedPoint, fixedPoint, bool) {
+	if a.Trader == nil || a.Trader != b.Trader || a.Trader.backend != nil ||
+		a.Currency.Code != b.Currency.Code ||
+		!a.fixed.valid(a.Value) || !b.fixed.valid(b.Value) {
+
+		return fixedPoint{}, fixedPoint{}, false
+	}
+
+	return a.fixed, b.fixed, true
+}
+
+// addFixed returns a + b, or a - b if sub is true, using their fixed-point
+// representations. It returns false if the fast path can't be used, or if
+// the result would overflow
+func (a Amount) addFixed(b Amount, sub bool) (Amount, bool) {
+	f, g, ok := a.fastPath(b)
+	if !ok {
+		return emptyAmount, false
+	}
+	x, y, exp, ok := f.align(g)
+	if !ok {
+		return emptyAmount, false
+	}
+
+	if sub {
+		if y == math.MinInt64 {
+			return emptyAmount, false
+		}
+		y = -y
+	}
+	if (y > 0 && x > math.MaxInt64-y) || (y < 0 && x < math.MinInt64-y) {
+		return emptyAmount, false
+	}
+
+	v := decimal.New(x+y, exp)
+	return Amount{
+		Trader:   a.Trader,
+		Value:    v,
+		Currency: a.Currency,
+		fixed: fixedPoint{
+			of:    v,
+			units: x + y,
+			exp:   exp,
+			ok:    true,
+		},
+	}, true
+}
+
+// cmpFixed compares a and b using their fixed-point representations. It
+// returns false if the fast path can't be used
+func (a Amount) cmpFixed(b Amount) (int, bool) {
+	f, g, ok := a.fastPath(b)
+	if !ok {
+		return 0, false
+	}
+	x, y, _, ok := f.align(g)
+	if !ok {
+		return 0, false
+	}
+
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	}
+	return 0, true
+}
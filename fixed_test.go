@@ -0,0 +1,147 @@
+package trader
+
+import (
+	"math"
+	"testing"
+
+	"github.com/processout/decimal"
+)
+
+// slow returns a copy of a whose fixed-point representation is unknown, so
+// that operations on it go through the decimal package
+func slow(a Amount) Amount {
+	a.fixed = fixedPoint{}
+	return a
+}
+
+func TestAmount_FixedPoint(t *testing.T) {
+	trader := getTrader()
+	tests := []struct {
+		a, b string
+	}{
+		{"10.25", "4.75"},
+		{"10.25", "-4.755"},
+		{"0", "0.01"},
+		{"1000000", "0.000001"},
+		{"-3.1", "3.10"},
+	}
+
+	for _, test := range tests {
+		a, _ := trader.NewAmountFromString(test.a, "usd")
+		b, _ := trader.NewAmountFromString(test.b, "usd")
+
+		sum, _ := a.Add(b)
+		expected, _ := slow(a).Add(slow(b))
+		if sum.Value.String() != expected.Value.String() || !sum.fixed.valid(sum.Value) {
+			t.Errorf("%s + %s should have given %s, got %s", test.a, test.b, expected.Value, sum.Value)
+		}
+
+		diff, _ := a.Sub(b)
+		expected, _ = slow(a).Sub(slow(b))
+		if diff.Value.String() != expected.Value.String() {
+			t.Errorf("%s - %s should have given %s, got %s", test.a, test.b, expected.Value, diff.Value)
+		}
+
+		r, _ := a.Cmp(b)
+		e, _ := slow(a).Cmp(slow(b))
+		if r != e {
+			t.Errorf("Comparing %s and %s should have given %d, got %d", test.a, test.b, e, r)
+		}
+	}
+}
+
+func TestAmount_FixedPointFallback(t *testing.T) {
+	trader := getTrader()
+	a, _ := trader.NewAmount(decimal.New(math.MaxInt64, -2), "usd")
+	b, _ := trader.NewAmountFromString("0.01", "usd")
+
+	// The sum overflows an int64, and is computed by the decimal package
+	if _, ok := a.addFixed(b, false); ok {
+		t.Error("The fast path should have overflowed")
+	}
+	sum, err := a.Add(b)
+	if err != nil || sum.Value.String() != "92233720368547758.08" {
+		t.Errorf("The sum should have been promoted to a decimal: %s", sum.Value)
+	}
+
+	// Aligning the exponents overflows
+	c, _ := trader.NewAmountFromString("0.000001", "usd")
+	if _, ok := a.cmpFixed(c); ok {
+		t.Error("The fast path should have overflowed")
+	}
+
+	// The value was replaced, so the fixed-point representation is stale
+	b.Value = decimal.NewFromFloat(5)
+	if _, ok := b.cmpFixed(b); ok {
+		t.Error("The stale representation shouldn't have been used")
+	}
+	if r, _ := b.Cmp(c); r <= 0 {
+		t.Error("Answer should have been positive (5 > 0.000001)")
+	}
+
+	e, _ := trader.NewAmountFromString("1", "eur")
+	if _, ok := b.addFixed(e, false); ok {
+		t.Error("The fast path shouldn't have been used across currencies")
+	}
+}
+
+func benchmarkAmounts() (Amount, Amount) {
+	trader := getTrader()
+	x, _ := trader.NewAmountFromString("1234.56", "usd")
+	y, _ := trader.NewAmountFromString("78.90", "usd")
+	return x, y
+}
+
+func BenchmarkAmount_Add(b *testing.B) {
+	x, y := benchmarkAmounts()
+
+	b.Run("fixed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			x.Add(y)
+		}
+	})
+	b.Run("decimal", func(b *testing.B) {
+		x, y := slow(x), slow(y)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			x.Add(y)
+		}
+	})
+}
+
+func BenchmarkAmount_Sub(b *testing.B) {
+	x, y := benchmarkAmounts()
+
+	b.Run("fixed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			x.Sub(y)
+		}
+	})
+	b.Run("decimal", func(b *testing.B) {
+		x, y := slow(x), slow(y)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			x.Sub(y)
+		}
+	})
+}
+
+func BenchmarkAmount_Cmp(b *testing.B) {
+	x, y := benchmarkAmounts()
+
+	b.Run("fixed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			x.Cmp(y)
+		}
+	})
+	b.Run("decimal", func(b *testing.B) {
+		x, y := slow(x), slow(y)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			x.Cmp(y)
+		}
+	})
+}
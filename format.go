@@ -0,0 +1,146 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/processout/decimal"
+)
+
+// places returns the number of decimal places of the currency of the
+// Amount, or 0 if the currency is unknown
+func (a Amount) places() int32 {
+	info := a.Currency.Code.Information()
+	if info == nil {
+		return 0
+	}
+
+	return int32(info.Places)
+}
+
+// text returns the value of the Amount with at least the decimal places of
+// its currency, but without ever losing precision
+func (a Amount) text() string {
+	places := a.places()
+	if exp := a.Value.Exponent(); -exp > places {
+		places = -exp
+	}
+
+	return a.Value.StringFixed(places)
+}
+
+// minorUnits returns the value of the Amount in the minor unit of its
+// currency, truncated
+func (a Amount) minorUnits() int64 {
+	return a.Value.Mul(decimal.New(1, a.places())).IntPart()
+}
+
+// Format implements fmt.Formatter. The supported verbs are:
+//   - %v and %s: the value with the decimal places of the currency (12.34)
+//   - %+v and %+s: the canonical form, prefixed by the currency code
+//     (USD 12.34)
+//   - %d: the value in the minor unit of the currency (1234)
+//   - %f: the value with the given precision, %.2f giving 12.34. The
+//     precision defaults to the decimal places of the currency, and the +
+//     flag prefixes the currency code
+//
+// The width and the - flag are supported by every verb
+func (a Amount) Format(f fmt.State, verb rune) {
+	var s string
+	switch verb {
+	case 'v', 's':
+		s = a.text()
+	case 'd':
+		s = strconv.FormatInt(a.minorUnits(), 10)
+	case 'f', 'F':
+		places := a.places()
+		if p, ok := f.Precision(); ok {
+			places = int32(p)
+		}
+		s = a.Value.StringFixed(places)
+	default:
+		fmt.Fprintf(f, "%%!%c(trader.Amount=%s %s)", verb, a.Currency.Code, a.text())
+		return
+	}
+
+	if verb != 'd' && f.Flag('+') {
+		s = string(a.Currency.Code) + " " + s
+	}
+
+	if w, ok := f.Width(); ok && len(s) < w {
+		pad := strings.Repeat(" ", w-len(s))
+		if f.Flag('-') {
+			s += pad
+		} else {
+			s = pad + s
+		}
+	}
+	fmt.Fprint(f, s)
+}
+
+// MarshalText implements encoding.TextMarshaler. The Amount is marshaled in
+// its canonical form: its currency code followed by its value, e.g.
+// "USD 12.34"
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(string(a.Currency.Code) + " " + a.text()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, and parses an Amount
+// in its canonical form. If the Amount already has a Trader, the currency
+// must be supported by it. Otherwise, the currency must be part of ISO
+// 4217, and the Amount needs a Trader before being converted
+func (a *Amount) UnmarshalText(text []byte) error {
+	fields := strings.Fields(string(text))
+	if len(fields) != 2 {
+		return fmt.Errorf("The amount `%s' is not of the form `CODE VALUE'.", text)
+	}
+
+	code := CurrencyCode(fields[0])
+	d, err := decimal.NewFromString(fields[1])
+	if err != nil {
+		return err
+	}
+
+	if a.Trader != nil && len(a.Trader.Currencies) > 0 {
+		n, err := a.Trader.NewAmount(d, code)
+		if err != nil {
+			return err
+		}
+		*a = n
+		return nil
+	}
+
+	c, err := NewCurrency(code, decimal.Decimal{})
+	if err != nil {
+		return err
+	}
+	a.Value = d
+	a.Currency = c
+	return nil
+}
+
+// amountJSON has the fields of an Amount, but none of its methods, so that
+// it is encoded to JSON as a struct rather than as text
+type amountJSON Amount
+
+// MarshalJSON implements json.Marshaler. The Amount is kept encoded as an
+// object, rather than in its text form
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(amountJSON(a))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Both the object form and the
+// canonical text form of an Amount are accepted
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return a.UnmarshalText([]byte(s))
+	}
+
+	return json.Unmarshal(data, (*amountJSON)(a))
+}
@@ -0,0 +1,119 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/processout/decimal"
+)
+
+func TestAmount_Format(t *testing.T) {
+	trader := getTrader()
+	amount, _ := trader.NewAmountFromString("12.3", "usd")
+	precise, _ := trader.NewAmountFromString("12.345", "usd")
+
+	tests := []struct {
+		format   string
+		amount   Amount
+		expected string
+	}{
+		{"%v", amount, "12.30"},
+		{"%s", amount, "12.30"},
+		{"%+v", amount, "USD 12.30"},
+		{"%v", precise, "12.345"},
+		{"%d", amount, "1230"},
+		{"%.2f", precise, "12.35"},
+		{"%f", amount, "12.30"},
+		{"%+.1f", amount, "USD 12.3"},
+		{"%8v", amount, "   12.30"},
+		{"%-8v|", amount, "12.30   |"},
+		{"%x", amount, "%!x(trader.Amount=USD 12.30)"},
+		{"%v", emptyAmount, "0"},
+	}
+
+	for _, test := range tests {
+		if s := fmt.Sprintf(test.format, test.amount); s != test.expected {
+			t.Errorf("%q should have given %q, got %q", test.format, test.expected, s)
+		}
+	}
+}
+
+func TestAmount_MarshalText(t *testing.T) {
+	trader := getTrader()
+	amount, _ := trader.NewAmountFromString("12.3", "eur")
+
+	text, err := amount.MarshalText()
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if string(text) != "EUR 12.30" {
+		t.Errorf("The amount was incorrectly marshaled: %s", text)
+	}
+
+	n := Amount{Trader: &trader}
+	if err := n.UnmarshalText(text); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !n.Equal(amount) || !n.Currency.Value.Equals(amount.Currency.Value) {
+		t.Error("The amount was incorrectly unmarshaled")
+	}
+
+	if err := n.UnmarshalText([]byte("GBP 12.30")); err == nil {
+		t.Error("The currency should have been refused by the trader")
+	}
+
+	var bare Amount
+	if err := bare.UnmarshalText([]byte("gbp 12.30")); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !bare.Currency.Is("GBP") || !bare.Value.Equals(decimal.NewFromFloat(12.3)) {
+		t.Error("The amount was incorrectly unmarshaled")
+	}
+
+	// The rates come from the Trader attached afterwards
+	bare = Amount{}
+	if err := bare.UnmarshalText([]byte("EUR 10")); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	bare.Trader = &trader
+	n, err = bare.ToCurrency("usd")
+	if err != nil || n.Value.String() != "12.5" {
+		t.Error("The amount was incorrectly converted: " + n.Value.String())
+	}
+
+	for _, s := range []string{"", "USD", "USD 12 34", "USD twelve", "LEL 12.30"} {
+		if err := bare.UnmarshalText([]byte(s)); err == nil {
+			t.Errorf("%q should have been refused", s)
+		}
+	}
+}
+
+func TestAmount_MarshalJSON(t *testing.T) {
+	trader := getTrader()
+	amount, _ := trader.NewAmountFromString("12.3", "usd")
+
+	b, err := json.Marshal(amount)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil || fields["currency"] == nil {
+		t.Errorf("The amount should have been encoded as an object: %s", b)
+	}
+
+	var n Amount
+	if err := json.Unmarshal(b, &n); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !n.Currency.Is("usd") || !n.Value.Equals(amount.Value) {
+		t.Error("The amount was incorrectly unmarshaled")
+	}
+
+	if err := json.Unmarshal([]byte(`"EUR 4.20"`), &n); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !n.Currency.Is("eur") || !n.Value.Equals(decimal.NewFromFloat(4.2)) {
+		t.Error("The text form should have been accepted")
+	}
+}
@@ -0,0 +1,157 @@
+// Package ratefile loads the rates of a Trader from JSON or CSV files.
+//
+// JSON files have the same shape as a marshaled trader.Trader:
+//
+//	{
+//	  "currencies": [{"code": "USD", "value": "1"}, {"code": "EUR", "value": "0.8"}],
+//	  "base_currency": {"code": "USD", "value": "1"}
+//	}
+//
+// JSON files written by trader.WriteSnapshot are also accepted, and their
+// integrity is verified.
+//
+// CSV files have a code and a value column, with an optional header. The
+// base currency is the first one with a value of 1:
+//
+//	code,value
+//	USD,1
+//	EUR,0.8
+//
+// The rates are validated with trader.Trader.Validate, so that a bad rates
+// file is never used.
+package ratefile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+// Load loads a Trader from the file at the given path. Files with a .csv
+// extension are read as CSV, any other file is read as JSON
+func Load(path string) (trader.Trader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return trader.Trader{}, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return ReadCSV(f)
+	}
+	return ReadJSON(f)
+}
+
+// ReadJSON reads a Trader from its JSON representation, or from a snapshot
+// written by trader.WriteSnapshot. The currency codes are verified, and the
+// base currency must be part of the currencies
+func ReadJSON(r io.Reader) (trader.Trader, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return trader.Trader{}, err
+	}
+
+	var s struct {
+		SHA256 string `json:"sha256"`
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return trader.Trader{}, err
+	}
+	if s.SHA256 != "" {
+		snapshot, err := trader.ReadSnapshot(bytes.NewReader(b))
+		if err != nil {
+			return trader.Trader{}, err
+		}
+		t, err := snapshot.Trader()
+		if err != nil {
+			return trader.Trader{}, err
+		}
+		return validate(t)
+	}
+
+	var t trader.Trader
+	if err := json.Unmarshal(b, &t); err != nil {
+		return trader.Trader{}, err
+	}
+	if t.BaseCurrency.Code == "" {
+		return trader.Trader{}, errors.New("The rates file has no base currency.")
+	}
+
+	currencies := make(trader.Currencies, 0, len(t.Currencies))
+	for _, c := range t.Currencies {
+		n, err := trader.NewCurrency(c.Code, c.Value)
+		if err != nil {
+			return trader.Trader{}, err
+		}
+		currencies = append(currencies, n)
+	}
+
+	n, err := trader.New(currencies, t.BaseCurrency.Code)
+	if err != nil {
+		return trader.Trader{}, err
+	}
+	n.SnapshotID = t.SnapshotID
+	n.SnapshotTime = t.SnapshotTime
+	return validate(n)
+}
+
+// ReadCSV reads a Trader from CSV records of a currency code and its value.
+// A first record of "code,value" is skipped as a header. The base currency
+// is the first currency with a value of 1
+func ReadCSV(r io.Reader) (trader.Trader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return trader.Trader{}, err
+	}
+	if len(records) > 0 && strings.EqualFold(records[0][0], "code") {
+		records = records[1:]
+	}
+
+	var base trader.CurrencyCode
+	one := decimal.New(1, 0)
+	currencies := make(trader.Currencies, 0, len(records))
+	for _, rec := range records {
+		v, err := decimal.NewFromString(rec[1])
+		if err != nil {
+			return trader.Trader{}, err
+		}
+		c, err := trader.NewCurrency(trader.CurrencyCode(rec[0]), v)
+		if err != nil {
+			return trader.Trader{}, err
+		}
+		if base == "" && v.Cmp(one) == 0 {
+			base = c.Code
+		}
+		currencies = append(currencies, c)
+	}
+	if base == "" {
+		return trader.Trader{}, errors.New("The rates file has no currency with a value of 1 to use as base currency.")
+	}
+
+	t, err := trader.New(currencies, base)
+	if err != nil {
+		return trader.Trader{}, err
+	}
+	return validate(t)
+}
+
+// validate returns the given Trader, or an error if its rates are invalid
+func validate(t trader.Trader) (trader.Trader, error) {
+	if err := t.Validate(); err != nil {
+		return trader.Trader{}, err
+	}
+
+	return t, nil
+}
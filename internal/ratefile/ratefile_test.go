@@ -0,0 +1,120 @@
+package ratefile
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+func TestReadJSON(t *testing.T) {
+	_, err := ReadJSON(strings.NewReader(`{`))
+	if err == nil {
+		t.Error("There should have been an error")
+	}
+
+	_, err = ReadJSON(strings.NewReader(`{"currencies": [{"code": "usd", "value": "1"}]}`))
+	if err == nil {
+		t.Error("There should have been an error")
+	}
+
+	_, err = ReadJSON(strings.NewReader(`{"currencies": [{"code": "lel", "value": "1"}],
+		"base_currency": {"code": "lel", "value": "1"}}`))
+	if err == nil {
+		t.Error("There should have been an error")
+	}
+
+	tr, err := ReadJSON(strings.NewReader(`{"currencies": [{"code": "usd", "value": "1"},
+		{"code": "eur", "value": "0.8"}], "base_currency": {"code": "usd", "value": "1"}}`))
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if len(tr.Currencies) != 2 || !tr.BaseCurrency.Is("usd") {
+		t.Error("The trader was incorrectly read")
+	}
+	if c, err := tr.Currencies.Find("eur"); err != nil || c.Code != "EUR" {
+		t.Error("The currency codes should have been formatted")
+	}
+}
+
+func TestReadJSON_Snapshot(t *testing.T) {
+	usd, _ := trader.NewCurrency("USD", decimal.New(1, 0))
+	tr, _ := trader.New(trader.Currencies{usd}, "usd")
+	now := time.Now()
+
+	var b bytes.Buffer
+	trader.WriteSnapshot(&b, trader.NewSnapshot(tr, "test", now, now))
+	data := b.String()
+
+	tr, err := ReadJSON(strings.NewReader(data))
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if tr.SnapshotID == "" || len(tr.Currencies) != 1 {
+		t.Error("The trader should have been read from the snapshot")
+	}
+
+	_, err = ReadJSON(strings.NewReader(strings.Replace(data, `"test"`, `"lel"`, 1)))
+	if err == nil {
+		t.Error("The tampered snapshot should have been refused")
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	_, err := ReadCSV(strings.NewReader("usd,1,2\n"))
+	if err == nil {
+		t.Error("There should have been an error")
+	}
+
+	_, err = ReadCSV(strings.NewReader("usd,lel\n"))
+	if err == nil {
+		t.Error("There should have been an error")
+	}
+
+	_, err = ReadCSV(strings.NewReader("eur,0.8\n"))
+	if err == nil {
+		t.Error("There should have been an error")
+	}
+
+	_, err = ReadCSV(strings.NewReader("usd,1\neur,0\n"))
+	if err == nil {
+		t.Error("The invalid rates should have been refused")
+	}
+
+	tr, err := ReadCSV(strings.NewReader("code,value\neur,0.8\nusd, 1\n"))
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if len(tr.Currencies) != 2 || !tr.BaseCurrency.Is("usd") {
+		t.Error("The trader was incorrectly read")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := os.MkdirTemp("", "ratefile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := Load(filepath.Join(dir, "none.json")); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	path := filepath.Join(dir, "rates.CSV")
+	if err := os.WriteFile(path, []byte("usd,1\neur,0.8\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := Load(path)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if len(tr.Currencies) != 2 {
+		t.Error("The file should have been read as CSV")
+	}
+}
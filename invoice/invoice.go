@@ -0,0 +1,203 @@
+// Package invoice computes the totals of invoices made of line items, with
+// the different tax rounding strategies and price conventions used by tax
+// engines.
+package invoice
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+// Rounding is the strategy used to round the taxes of an Invoice
+type Rounding int
+
+const (
+	// PerLine rounds the tax of every line, and sums the rounded values
+	PerLine Rounding = iota
+	// PerInvoice sums the unrounded lines for each tax rate, and rounds the
+	// tax computed on those sums
+	PerInvoice
+)
+
+// Pricing tells whether the unit prices of an Invoice include the taxes
+type Pricing int
+
+const (
+	// TaxExclusive prices don't include the taxes, which are added on top
+	TaxExclusive Pricing = iota
+	// TaxInclusive prices already include the taxes
+	TaxInclusive
+)
+
+// Line is a line item of an Invoice
+type Line struct {
+	Description string `json:"description"`
+	// UnitPrice is the price of a single unit
+	UnitPrice trader.Amount `json:"unit_price"`
+	// Quantity is the number of units
+	Quantity decimal.Decimal `json:"quantity"`
+	// TaxRate is the tax rate applied to the line, e.g. 0.2 for 20%
+	TaxRate decimal.Decimal `json:"tax_rate"`
+	// Discount is the fraction of the line price that is discounted, e.g.
+	// 0.1 for 10%
+	Discount decimal.Decimal `json:"discount"`
+}
+
+// Invoice is a list of line items to be totaled in a single currency
+type Invoice struct {
+	// Currency is the currency of the totals. Lines in other currencies are
+	// converted using the Trader of their unit price
+	Currency trader.CurrencyCode `json:"currency"`
+	Lines    []Line              `json:"lines"`
+	Rounding Rounding            `json:"rounding"`
+	Pricing  Pricing             `json:"pricing"`
+}
+
+// LineTotal is the total of a single Line. Its values are rounded to the
+// currency decimal places only with the PerLine rounding
+type LineTotal struct {
+	Line  Line          `json:"line"`
+	Net   trader.Amount `json:"net"`
+	Tax   trader.Amount `json:"tax"`
+	Gross trader.Amount `json:"gross"`
+}
+
+// TaxTotal is the total of all the lines sharing the same tax rate
+type TaxTotal struct {
+	Rate  decimal.Decimal `json:"rate"`
+	Net   trader.Amount   `json:"net"`
+	Tax   trader.Amount   `json:"tax"`
+	Gross trader.Amount   `json:"gross"`
+}
+
+// Totals are the totals of an Invoice
+type Totals struct {
+	Lines []LineTotal `json:"lines"`
+	// Taxes is the breakdown of the totals by tax rate, sorted by rate
+	Taxes []TaxTotal `json:"taxes"`
+	// Subtotal is the total without taxes
+	Subtotal trader.Amount `json:"subtotal"`
+	// Tax is the total of the taxes
+	Tax trader.Amount `json:"tax"`
+	// Total is the total including taxes
+	Total trader.Amount `json:"total"`
+}
+
+// Totals computes the totals of the Invoice. An error is returned if the
+// Invoice has no lines, if its currency has no decimal places, e.g. XAU, or
+// if a line could not be converted to the Invoice currency
+func (inv Invoice) Totals() (Totals, error) {
+	if len(inv.Lines) == 0 {
+		return Totals{}, errors.New("The invoice has no lines.")
+	}
+
+	t := inv.Lines[0].UnitPrice.Trader
+	zero, err := t.NewAmount(decimal.New(0, 0), inv.Currency)
+	if err != nil {
+		return Totals{}, err
+	}
+	places := int32(zero.Currency.DecimalPlaces())
+	if places < 0 {
+		return Totals{}, fmt.Errorf("The currency %s has no decimal places to round the invoice to.",
+			zero.Currency.Code)
+	}
+	amount := func(v decimal.Decimal) trader.Amount {
+		a := zero
+		a.Value = v
+		return a
+	}
+
+	totals := Totals{
+		Lines: make([]LineTotal, 0, len(inv.Lines)),
+	}
+	groups := map[string]*TaxTotal{}
+	one := decimal.New(1, 0)
+	for _, l := range inv.Lines {
+		price, err := l.UnitPrice.ToCurrency(inv.Currency)
+		if err != nil {
+			return Totals{}, err
+		}
+		v := price.Value.Mul(l.Quantity).Mul(one.Sub(l.Discount))
+
+		net, tax, gross := inv.split(v, l.TaxRate)
+		if inv.Rounding == PerLine {
+			net, tax, gross = inv.split(v.Round(places), l.TaxRate)
+			tax = tax.Round(places)
+			net, gross = inv.complete(net, tax, gross)
+		}
+		totals.Lines = append(totals.Lines, LineTotal{
+			Line:  l,
+			Net:   amount(net),
+			Tax:   amount(tax),
+			Gross: amount(gross),
+		})
+
+		key := l.TaxRate.String()
+		g, ok := groups[key]
+		if !ok {
+			g = &TaxTotal{
+				Rate:  l.TaxRate,
+				Net:   zero,
+				Tax:   zero,
+				Gross: zero,
+			}
+			groups[key] = g
+		}
+		g.Net.Value = g.Net.Value.Add(net)
+		g.Tax.Value = g.Tax.Value.Add(tax)
+		g.Gross.Value = g.Gross.Value.Add(gross)
+	}
+
+	totals.Subtotal, totals.Tax, totals.Total = zero, zero, zero
+	for _, g := range groups {
+		if inv.Rounding == PerInvoice {
+			base := g.Net.Value
+			if inv.Pricing == TaxInclusive {
+				base = g.Gross.Value
+			}
+
+			net, tax, gross := inv.split(base.Round(places), g.Rate)
+			tax = tax.Round(places)
+			g.Net.Value, g.Gross.Value = inv.complete(net, tax, gross)
+			g.Tax.Value = tax
+		}
+
+		totals.Taxes = append(totals.Taxes, *g)
+		totals.Subtotal.Value = totals.Subtotal.Value.Add(g.Net.Value)
+		totals.Tax.Value = totals.Tax.Value.Add(g.Tax.Value)
+		totals.Total.Value = totals.Total.Value.Add(g.Gross.Value)
+	}
+	sort.Slice(totals.Taxes, func(i, j int) bool {
+		return totals.Taxes[i].Rate.Cmp(totals.Taxes[j].Rate) < 0
+	})
+
+	return totals, nil
+}
+
+// split splits the price v into its net, tax and gross values at the given
+// tax rate, according to the pricing of the Invoice
+func (inv Invoice) split(v, rate decimal.Decimal) (net, tax, gross decimal.Decimal) {
+	if inv.Pricing == TaxInclusive {
+		gross = v
+		net = v.Div(decimal.New(1, 0).Add(rate))
+		return net, gross.Sub(net), gross
+	}
+
+	net = v
+	tax = v.Mul(rate)
+	return net, tax, net.Add(tax)
+}
+
+// complete recomputes the net or gross value from the rounded tax,
+// according to the pricing of the Invoice, so that net + tax = gross
+func (inv Invoice) complete(net, tax, gross decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	if inv.Pricing == TaxInclusive {
+		return gross.Sub(tax), gross
+	}
+
+	return net, net.Add(tax)
+}
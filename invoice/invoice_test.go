@@ -0,0 +1,125 @@
+package invoice
+
+import (
+	"testing"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+func getTrader() trader.Trader {
+	c1, _ := trader.NewCurrency("USD", decimal.NewFromFloat(1))
+	c2, _ := trader.NewCurrency("EUR", decimal.NewFromFloat(0.8))
+	c3, _ := trader.NewCurrency("XAU", decimal.NewFromFloat(0.0005))
+	t, _ := trader.New(trader.Currencies{c1, c2, c3}, "usd")
+	return t
+}
+
+func getLines(price string, rates ...string) []Line {
+	t := getTrader()
+	lines := []Line{}
+	for _, r := range rates {
+		p, _ := t.NewAmountFromString(price, "eur")
+		rate, _ := decimal.NewFromString(r)
+		lines = append(lines, Line{
+			UnitPrice: p,
+			Quantity:  decimal.New(1, 0),
+			TaxRate:   rate,
+		})
+	}
+
+	return lines
+}
+
+func TestInvoice_Totals(t *testing.T) {
+	inv := Invoice{Currency: "eur"}
+	if _, err := inv.Totals(); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	inv.Lines = getLines("0.99", "0.2", "0.2", "0.2")
+	inv.Currency = "gel"
+	if _, err := inv.Totals(); err == nil {
+		t.Error("There should have been an error")
+	}
+	inv.Currency = "xau"
+	if _, err := inv.Totals(); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	inv.Currency = "eur"
+	totals, err := inv.Totals()
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if totals.Subtotal.String(2) != "2.97" {
+		t.Error("The subtotal was incorrectly computed: " + totals.Subtotal.String(2))
+	}
+	if totals.Tax.String(2) != "0.60" || totals.Total.String(2) != "3.57" {
+		t.Error("The per line tax was incorrectly computed: " + totals.Tax.String(2))
+	}
+	if totals.Lines[0].Tax.String(3) != "0.200" {
+		t.Error("The line tax should have been rounded: " + totals.Lines[0].Tax.String(3))
+	}
+
+	inv.Rounding = PerInvoice
+	totals, err = inv.Totals()
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if totals.Tax.String(2) != "0.59" || totals.Total.String(2) != "3.56" {
+		t.Error("The per invoice tax was incorrectly computed: " + totals.Tax.String(2))
+	}
+	if totals.Lines[0].Tax.String(3) != "0.198" {
+		t.Error("The line tax shouldn't have been rounded: " + totals.Lines[0].Tax.String(3))
+	}
+}
+
+func TestInvoice_TotalsInclusive(t *testing.T) {
+	inv := Invoice{
+		Currency: "eur",
+		Lines:    getLines("1.19", "0.19", "0.19", "0.19"),
+		Pricing:  TaxInclusive,
+	}
+
+	totals, err := inv.Totals()
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if totals.Total.String(2) != "3.57" || totals.Tax.String(2) != "0.57" ||
+		totals.Subtotal.String(2) != "3.00" {
+
+		t.Error("The tax inclusive totals were incorrectly computed")
+	}
+}
+
+func TestInvoice_TotalsBreakdown(t *testing.T) {
+	inv := Invoice{
+		Currency: "usd",
+		Lines:    getLines("10", "0.2", "0.055", "0.2"),
+	}
+	inv.Lines[1].Quantity = decimal.New(2, 0)
+	inv.Lines[2].Discount, _ = decimal.NewFromString("0.5")
+
+	totals, err := inv.Totals()
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if len(totals.Taxes) != 2 {
+		t.Error("There should have been one total per tax rate")
+	}
+	if totals.Taxes[0].Rate.String() != "0.055" ||
+		totals.Taxes[0].Net.String(2) != "25.00" ||
+		totals.Taxes[0].Tax.String(2) != "1.38" {
+
+		t.Error("The reduced rate total was incorrectly computed")
+	}
+	if totals.Taxes[1].Net.String(2) != "18.75" ||
+		totals.Taxes[1].Tax.String(2) != "3.75" {
+
+		t.Error("The standard rate total was incorrectly computed")
+	}
+	if totals.Total.String(2) != "48.88" || !totals.Total.Currency.Is("usd") {
+		t.Error("The total was incorrectly computed: " + totals.Total.String(2))
+	}
+}
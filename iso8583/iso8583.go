@@ -0,0 +1,193 @@
+// Package iso8583 encodes and decodes amounts in the formats of the fields
+// of ISO 8583 card acquiring messages: amounts in minor units (fields 4, 5
+// and 6), numeric currency codes (fields 49, 50 and 51), and conversion
+// rates (fields 9 and 10).
+package iso8583
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+const (
+	// AmountLength is the length of the amount fields
+	AmountLength = 12
+	// CurrencyLength is the length of the currency code fields
+	CurrencyLength = 3
+	// RateLength is the length of the conversion rate fields
+	RateLength = 8
+
+	// maxRateDigits is the number of digits of the conversion rates,
+	// following their decimal position
+	maxRateDigits = 7
+)
+
+// maxAmount is the greatest amount, in minor units, of an amount field
+var maxAmount = decimal.New(1, AmountLength)
+
+// EncodeAmount encodes the Amount as an amount field, in minor units
+// zero-padded to 12 digits, e.g. "000000012345" for 123.45 EUR, and its
+// currency as a numeric currency code field, e.g. "978". An error is
+// returned if the Amount is negative, too large, has more decimal places
+// than its currency, or if its currency has no minor unit, e.g. XAU
+func EncodeAmount(a trader.Amount) (string, string, error) {
+	currency, err := EncodeCurrency(a.Currency.Code)
+	if err != nil {
+		return "", "", err
+	}
+
+	places, err := minorUnit(a.Currency.Code)
+	if err != nil {
+		return "", "", err
+	}
+	minor := a.Value.Mul(decimal.New(1, places))
+	if !minor.Rat().IsInt() {
+		return "", "", fmt.Errorf("The amount %s has more than %d decimal places.", a.Value, places)
+	}
+	if minor.Cmp(decimal.New(0, 0)) < 0 || minor.Cmp(maxAmount) >= 0 {
+		return "", "", fmt.Errorf("The amount %s can't be encoded on %d digits.", a.Value, AmountLength)
+	}
+
+	return fmt.Sprintf("%0*d", AmountLength, minor.IntPart()), currency, nil
+}
+
+// DecodeAmount decodes an amount field and its numeric currency code field
+// into an Amount created by the given Trader. An error is returned if the
+// fields are malformed, if the currency has no minor unit, or if it is not
+// supported by the Trader
+func DecodeAmount(t *trader.Trader, amount, currency string) (trader.Amount, error) {
+	code, err := DecodeCurrency(currency)
+	if err != nil {
+		return trader.Amount{}, err
+	}
+
+	minor, err := parseDigits(amount, AmountLength)
+	if err != nil {
+		return trader.Amount{}, err
+	}
+
+	if t == nil {
+		return trader.Amount{}, trader.CurrencyNotFoundError{Code: code}
+	}
+
+	places, err := minorUnit(code)
+	if err != nil {
+		return trader.Amount{}, err
+	}
+	return t.NewAmount(decimal.New(minor, -places), code)
+}
+
+// minorUnit returns the number of decimal places of the minor unit of the
+// currency of the given code. An error is returned if the currency has no
+// minor unit, e.g. XAU
+func minorUnit(code trader.CurrencyCode) (int32, error) {
+	places := code.Information().Places
+	if places < 0 {
+		return 0, fmt.Errorf("The currency %s has no minor unit.", code)
+	}
+
+	return int32(places), nil
+}
+
+// EncodeCurrency encodes the given currency code as a numeric currency code
+// field, e.g. "840" for USD. An error is returned if the currency has no
+// ISO 4217 number
+func EncodeCurrency(code trader.CurrencyCode) (string, error) {
+	info := code.Information()
+	if info == nil {
+		return "", trader.UnknownCurrencyError{Code: code}
+	}
+	if info.Number == 0 {
+		return "", fmt.Errorf("The currency %s has no ISO 4217 number.", code)
+	}
+
+	return fmt.Sprintf("%03d", info.Number), nil
+}
+
+// DecodeCurrency decodes a numeric currency code field, e.g. "840" for USD
+func DecodeCurrency(field string) (trader.CurrencyCode, error) {
+	n, err := parseDigits(field, CurrencyLength)
+	if err != nil {
+		return "", err
+	}
+
+	if n > 0 {
+		for code, info := range trader.ValidCurrencies() {
+			if info.Number == uint(n) {
+				return code, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("The currency number %s is not part of ISO 4217.", field)
+}
+
+// EncodeRate encodes the given conversion rate as a conversion rate field:
+// the first digit is the number of decimal places of the 7 following
+// digits, e.g. "61234567" for 1.234567. The rate is rounded half away from
+// zero to the greatest number of decimal places that fits. An error is
+// returned if the rate is not positive, or is too large
+func EncodeRate(rate decimal.Decimal) (string, error) {
+	if rate.Cmp(decimal.New(0, 0)) <= 0 {
+		return "", fmt.Errorf("The conversion rate %s is not positive.", rate)
+	}
+
+	limit := decimal.New(1, maxRateDigits)
+	for places := int32(maxRateDigits); places >= 0; places-- {
+		digits := trader.RoundHalfUp.Round(rate, places).Mul(decimal.New(1, places))
+		if digits.Cmp(limit) < 0 {
+			if digits.Cmp(decimal.New(0, 0)) == 0 {
+				break
+			}
+			return fmt.Sprintf("%d%0*d", places, maxRateDigits, digits.IntPart()), nil
+		}
+	}
+
+	return "", fmt.Errorf("The conversion rate %s can't be encoded on %d digits.", rate, RateLength)
+}
+
+// DecodeRate decodes a conversion rate field
+func DecodeRate(field string) (decimal.Decimal, error) {
+	if _, err := parseDigits(field, RateLength); err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	places := int32(field[0] - '0')
+	if places > maxRateDigits {
+		return decimal.Decimal{}, fmt.Errorf("The conversion rate %s has an invalid decimal position.", field)
+	}
+	digits, err := strconv.ParseInt(field[1:], 10, 64)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return decimal.New(digits, -places), nil
+}
+
+// EncodeRateTo encodes the rate converting the Amount to the given currency,
+// as returned by Amount.RateTo, as a conversion rate field
+func EncodeRateTo(a trader.Amount, code trader.CurrencyCode) (string, error) {
+	rate, err := a.RateTo(code)
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeRate(rate)
+}
+
+// parseDigits parses a numeric field of the given length
+func parseDigits(field string, length int) (int64, error) {
+	if len(field) != length {
+		return 0, fmt.Errorf("The field %q should have %d digits.", field, length)
+	}
+	for _, c := range field {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("The field %q should only have digits.", field)
+		}
+	}
+
+	return strconv.ParseInt(field, 10, 64)
+}
@@ -0,0 +1,169 @@
+package iso8583
+
+import (
+	"testing"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+func getTrader() trader.Trader {
+	c1, _ := trader.NewCurrency("USD", decimal.NewFromFloat(1))
+	c2, _ := trader.NewCurrency("EUR", decimal.NewFromFloat(0.8))
+	c3, _ := trader.NewCurrency("JPY", decimal.NewFromFloat(110.25))
+	c4, _ := trader.NewCurrency("KWD", decimal.NewFromFloat(0.3))
+	c5, _ := trader.NewCurrency("XAU", decimal.NewFromFloat(0.0005))
+	t, _ := trader.New(trader.Currencies{c1, c2, c3, c4, c5}, "usd")
+	return t
+}
+
+func TestEncodeAmount(t *testing.T) {
+	tr := getTrader()
+	tests := []struct {
+		value    string
+		code     trader.CurrencyCode
+		amount   string
+		currency string
+	}{
+		{"123.45", "eur", "000000012345", "978"},
+		{"1234", "jpy", "000000001234", "392"},
+		{"1.5", "kwd", "000000001500", "414"},
+		{"0", "usd", "000000000000", "840"},
+		{"9999999999.99", "usd", "999999999999", "840"},
+	}
+
+	for _, test := range tests {
+		a, _ := tr.NewAmountFromString(test.value, test.code)
+		amount, currency, err := EncodeAmount(a)
+		if err != nil {
+			t.Errorf("%s %s: there shouldn't have been an error: %s", test.value, test.code, err)
+		}
+		if amount != test.amount || currency != test.currency {
+			t.Errorf("%s %s should have been encoded as %s %s, got %s %s",
+				test.value, test.code, test.amount, test.currency, amount, currency)
+		}
+
+		d, err := DecodeAmount(&tr, amount, currency)
+		if err != nil {
+			t.Errorf("%s %s: there shouldn't have been an error: %s", test.value, test.code, err)
+		}
+		if !d.Equal(a) || !d.Currency.Is(test.code) {
+			t.Errorf("%s %s was incorrectly decoded: %s", test.value, test.code, d.Value)
+		}
+	}
+
+	for _, v := range []string{"-1", "1.234", "10000000000"} {
+		a, _ := tr.NewAmountFromString(v, "usd")
+		if _, _, err := EncodeAmount(a); err == nil {
+			t.Errorf("%s should have been refused", v)
+		}
+	}
+
+	a, _ := tr.NewAmountFromString("60", "xau")
+	if _, _, err := EncodeAmount(a); err == nil {
+		t.Error("The amount in a currency without minor unit should have been refused")
+	}
+}
+
+func TestDecodeAmount(t *testing.T) {
+	tr := getTrader()
+
+	invalid := [][2]string{
+		{"12345", "978"},
+		{"00000001234a", "978"},
+		{"000000012345", "826"},
+		{"000000012345", "999"},
+		{"000000012345", "000"},
+		{"000000012345", "97"},
+		{"000000012345", "959"},
+	}
+	for _, f := range invalid {
+		if _, err := DecodeAmount(&tr, f[0], f[1]); err == nil {
+			t.Errorf("%s %s should have been refused", f[0], f[1])
+		}
+	}
+}
+
+func TestEncodeCurrency(t *testing.T) {
+	if s, err := EncodeCurrency("usd"); err != nil || s != "840" {
+		t.Errorf("USD was incorrectly encoded: %s", s)
+	}
+	if s, err := EncodeCurrency("all"); err != nil || s != "008" {
+		t.Errorf("ALL should have been zero-padded: %s", s)
+	}
+	if _, err := EncodeCurrency("btc"); err == nil {
+		t.Error("There should have been an error")
+	}
+	if _, err := EncodeCurrency("lel"); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	if code, err := DecodeCurrency("008"); err != nil || code != "ALL" {
+		t.Errorf("008 was incorrectly decoded: %s", code)
+	}
+}
+
+func TestEncodeRate(t *testing.T) {
+	tests := []struct {
+		rate     string
+		expected string
+	}{
+		{"1.234567", "61234567"},
+		{"0.8", "78000000"},
+		{"1.25", "61250000"},
+		{"110.25", "41102500"},
+		{"0.009070294784580499", "70090703"},
+		{"1234567.4", "01234567"},
+		{"9999999.4", "09999999"},
+	}
+
+	for _, test := range tests {
+		rate, _ := decimal.NewFromString(test.rate)
+		s, err := EncodeRate(rate)
+		if err != nil {
+			t.Errorf("%s: there shouldn't have been an error: %s", test.rate, err)
+		}
+		if s != test.expected {
+			t.Errorf("%s should have been encoded as %s, got %s", test.rate, test.expected, s)
+		}
+	}
+
+	for _, v := range []string{"0", "-1", "9999999.5", "0.00000001"} {
+		rate, _ := decimal.NewFromString(v)
+		if _, err := EncodeRate(rate); err == nil {
+			t.Errorf("%s should have been refused", v)
+		}
+	}
+}
+
+func TestDecodeRate(t *testing.T) {
+	rate, err := DecodeRate("61234567")
+	if err != nil || rate.String() != "1.234567" {
+		t.Errorf("The rate was incorrectly decoded: %s", rate)
+	}
+	rate, _ = DecodeRate("01234567")
+	if rate.String() != "1234567" {
+		t.Errorf("The rate was incorrectly decoded: %s", rate)
+	}
+
+	for _, f := range []string{"81234567", "6123456", "6123456a"} {
+		if _, err := DecodeRate(f); err == nil {
+			t.Errorf("%s should have been refused", f)
+		}
+	}
+}
+
+func TestEncodeRateTo(t *testing.T) {
+	tr := getTrader()
+	a, _ := tr.NewAmountFromString("10", "usd")
+
+	if s, err := EncodeRateTo(a, "eur"); err != nil || s != "78000000" {
+		t.Errorf("The rate was incorrectly encoded: %s", s)
+	}
+	if s, _ := EncodeRateTo(a, "jpy"); s != "41102500" {
+		t.Errorf("The rate was incorrectly encoded: %s", s)
+	}
+	if _, err := EncodeRateTo(a, "gbp"); err == nil {
+		t.Error("There should have been an error")
+	}
+}
@@ -0,0 +1,211 @@
+// Package ledger implements a double-entry ledger on top of the amounts of
+// the trader package. Transactions must be balanced in each of their
+// currencies, and currencies can be linked by exchanges recording the rate
+// given by a Trader.
+package ledger
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+// AccountType is the type of an Account
+type AccountType string
+
+const (
+	// Asset is the type of the accounts holding resources
+	Asset AccountType = "asset"
+	// Liability is the type of the accounts holding obligations
+	Liability AccountType = "liability"
+	// Equity is the type of the accounts holding the owners' interest
+	Equity AccountType = "equity"
+	// Income is the type of the accounts holding revenues
+	Income AccountType = "income"
+	// Expense is the type of the accounts holding costs
+	Expense AccountType = "expense"
+)
+
+// Account is an account of the Ledger, on which transactions are posted
+type Account struct {
+	ID   string      `json:"id"`
+	Name string      `json:"name"`
+	Type AccountType `json:"type"`
+}
+
+// Ledger is a double-entry ledger whose accounts and transactions are kept
+// in a Store. It is safe for concurrent use, as long as its Store is only
+// written through it
+type Ledger struct {
+	// mu serializes the checks and saves of Open and Post
+	mu    sync.Mutex
+	store Store
+}
+
+// New creates a new Ledger using the given Store
+func New(s Store) *Ledger {
+	return &Ledger{
+		store: s,
+	}
+}
+
+// Open opens the given Account in the Ledger. An error is returned if an
+// account with the same ID already exists
+func (l *Ledger) Open(a Account) error {
+	if a.ID == "" {
+		return fmt.Errorf("The account ID can't be empty.")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.store.Account(a.ID); err == nil {
+		return fmt.Errorf("The account %s already exists.", a.ID)
+	}
+
+	return l.store.SaveAccount(a)
+}
+
+// Post posts the given Transaction in the Ledger, after having verified it
+// is balanced and that all its accounts exist. If the Transaction has no
+// ID, an unused one is assigned to it. An error is returned if a
+// transaction with the same ID was already posted. The posted Transaction
+// is returned
+func (l *Ledger) Post(t Transaction) (Transaction, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, p := range t.Postings {
+		if _, err := l.store.Account(p.Account); err != nil {
+			return Transaction{}, err
+		}
+	}
+	if err := t.Validate(); err != nil {
+		return Transaction{}, err
+	}
+
+	ts, err := l.store.Transactions()
+	if err != nil {
+		return Transaction{}, err
+	}
+	ids := make(map[string]bool, len(ts))
+	for _, tx := range ts {
+		ids[tx.ID] = true
+	}
+
+	if t.ID == "" {
+		for n := len(ts) + 1; t.ID == "" || ids[t.ID]; n++ {
+			t.ID = strconv.Itoa(n)
+		}
+	} else if ids[t.ID] {
+		return Transaction{}, fmt.Errorf("The transaction %s already exists.", t.ID)
+	}
+
+	return t, l.store.SaveTransaction(t)
+}
+
+// Balance returns the balance of the given account, for each currency.
+// Debits are positive and credits negative
+func (l *Ledger) Balance(account string) (trader.Bag, error) {
+	if _, err := l.store.Account(account); err != nil {
+		return nil, err
+	}
+
+	ts, err := l.store.Transactions()
+	if err != nil {
+		return nil, err
+	}
+
+	b := trader.Bag{}
+	for _, t := range ts {
+		for _, p := range t.Postings {
+			if p.Account == account {
+				b = b.Add(p.Amount)
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// TrialBalanceLine is the line of a TrialBalance for a single Account. For
+// each currency, the balance of the account is either in Debits or in
+// Credits, and is always positive
+type TrialBalanceLine struct {
+	Account Account    `json:"account"`
+	Debits  trader.Bag `json:"debits"`
+	Credits trader.Bag `json:"credits"`
+}
+
+// TrialBalance lists the balances of all the accounts of a Ledger
+type TrialBalance struct {
+	Lines []TrialBalanceLine `json:"lines"`
+	// Debits is the total of the debit balances, for each currency
+	Debits trader.Bag `json:"debits"`
+	// Credits is the total of the credit balances, for each currency
+	Credits trader.Bag `json:"credits"`
+}
+
+// Balanced returns true if the total of the debit balances is equal to the
+// total of the credit balances, in every currency
+func (tb TrialBalance) Balanced() bool {
+	if len(tb.Debits) != len(tb.Credits) {
+		return false
+	}
+
+	for code, d := range tb.Debits {
+		c, ok := tb.Credits[code]
+		if !ok || d.Cmp(c) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TrialBalance computes the TrialBalance of the Ledger, with one line per
+// account in the order they were opened
+func (l *Ledger) TrialBalance() (TrialBalance, error) {
+	accounts, err := l.store.Accounts()
+	if err != nil {
+		return TrialBalance{}, err
+	}
+
+	tb := TrialBalance{
+		Lines:   make([]TrialBalanceLine, 0, len(accounts)),
+		Debits:  trader.Bag{},
+		Credits: trader.Bag{},
+	}
+	for _, a := range accounts {
+		b, err := l.Balance(a.ID)
+		if err != nil {
+			return TrialBalance{}, err
+		}
+
+		line := TrialBalanceLine{
+			Account: a,
+			Debits:  trader.Bag{},
+			Credits: trader.Bag{},
+		}
+		for _, code := range b.Codes() {
+			v := trader.Amount{
+				Value:    b[code],
+				Currency: trader.Currency{Code: code},
+			}
+			if v.Value.Cmp(decimal.New(0, 0)) > 0 {
+				line.Debits = line.Debits.Add(v)
+				tb.Debits = tb.Debits.Add(v)
+				continue
+			}
+
+			v.Value = decimal.New(0, 0).Sub(v.Value)
+			line.Credits = line.Credits.Add(v)
+			tb.Credits = tb.Credits.Add(v)
+		}
+		tb.Lines = append(tb.Lines, line)
+	}
+
+	return tb, nil
+}
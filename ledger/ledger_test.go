@@ -0,0 +1,158 @@
+package ledger
+
+import (
+	"sync"
+	"testing"
+)
+
+func getLedger() *Ledger {
+	l := New(NewMemoryStore())
+	l.Open(Account{ID: "cash-usd", Type: Asset})
+	l.Open(Account{ID: "cash-eur", Type: Asset})
+	l.Open(Account{ID: "fx", Type: Equity})
+	l.Open(Account{ID: "sales", Type: Income})
+	return l
+}
+
+func TestLedger_Open(t *testing.T) {
+	l := getLedger()
+
+	if err := l.Open(Account{ID: "sales"}); err == nil {
+		t.Error("There should have been an error")
+	}
+	if err := l.Open(Account{}); err == nil {
+		t.Error("There should have been an error")
+	}
+	if err := l.Open(Account{ID: "fees", Type: Expense}); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+}
+
+func TestLedger_Post(t *testing.T) {
+	l := getLedger()
+	tr := getTrader()
+	usd, _ := tr.NewAmountFromString("10", "usd")
+
+	tx := Transaction{}
+	tx.Debit("cash-gel", usd)
+	tx.Credit("sales", usd)
+	if _, err := l.Post(tx); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	tx = Transaction{}
+	tx.Debit("cash-usd", usd)
+	if _, err := l.Post(tx); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	tx.Credit("sales", usd)
+	tx, err := l.Post(tx)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if tx.ID != "1" {
+		t.Error("An ID should have been assigned to the transaction")
+	}
+
+	b, err := l.Balance("cash-usd")
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if b["USD"].StringFixed(2) != "10.00" {
+		t.Error("The balance was incorrectly computed: " + b["USD"].String())
+	}
+
+	if _, err := l.Balance("cash-gel"); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	tx.ID = "3"
+	if _, err := l.Post(tx); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if _, err := l.Post(tx); err == nil {
+		t.Error("There should have been an error")
+	}
+	tx.ID = ""
+	tx, err = l.Post(tx)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if tx.ID != "4" {
+		t.Error("An unused ID should have been assigned to the transaction: " + tx.ID)
+	}
+}
+
+func TestLedger_PostConcurrent(t *testing.T) {
+	l := getLedger()
+	tr := getTrader()
+	usd, _ := tr.NewAmountFromString("10", "usd")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			tx := Transaction{ID: "dup"}
+			tx.Debit("cash-usd", usd)
+			tx.Credit("sales", usd)
+			_, err := l.Post(tx)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	posted := 0
+	for err := range errs {
+		if err == nil {
+			posted++
+		}
+	}
+	if posted != 1 {
+		t.Errorf("The transaction should have been posted once, not %d times", posted)
+	}
+}
+
+func TestLedger_TrialBalance(t *testing.T) {
+	l := getLedger()
+	tr := getTrader()
+	usd, _ := tr.NewAmountFromString("10", "usd")
+	half, _ := tr.NewAmountFromString("5", "usd")
+
+	tx := Transaction{}
+	tx.Debit("cash-usd", usd)
+	tx.Credit("sales", usd)
+	l.Post(tx)
+
+	tx = Transaction{}
+	tx.Exchange("cash-usd", "cash-eur", "fx", half, "eur")
+	l.Post(tx)
+
+	tb, err := l.TrialBalance()
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if len(tb.Lines) != 4 {
+		t.Error("There should have been one line per account")
+	}
+	if !tb.Balanced() {
+		t.Error("The trial balance should have been balanced")
+	}
+	if tb.Debits["USD"].StringFixed(2) != "10.00" ||
+		tb.Credits["USD"].StringFixed(2) != "10.00" {
+
+		t.Error("The USD totals were incorrectly computed")
+	}
+	if tb.Lines[1].Debits["EUR"].StringFixed(2) != "4.00" {
+		t.Error("The EUR balance was incorrectly computed")
+	}
+	if tb.Lines[2].Credits["EUR"].StringFixed(2) != "4.00" ||
+		tb.Lines[2].Debits["USD"].StringFixed(2) != "5.00" {
+
+		t.Error("The trading account balance was incorrectly computed")
+	}
+}
@@ -0,0 +1,178 @@
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Store stores the accounts and transactions of a Ledger
+type Store interface {
+	// Account returns the account with the given ID, or an error if it
+	// could not be found
+	Account(id string) (Account, error)
+	// Accounts returns all the accounts, in the order they were saved
+	Accounts() ([]Account, error)
+	// SaveAccount saves the given account
+	SaveAccount(a Account) error
+	// Transactions returns all the transactions, in the order they were
+	// saved
+	Transactions() ([]Transaction, error)
+	// SaveTransaction saves the given transaction
+	SaveTransaction(t Transaction) error
+}
+
+// storeData is the content of a Store
+type storeData struct {
+	Accounts     []Account     `json:"accounts"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+// MemoryStore is a Store keeping its content in memory. It is safe for
+// concurrent use
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data storeData
+}
+
+// NewMemoryStore creates a new empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Account returns the account with the given ID, or an error if it could
+// not be found
+func (s *MemoryStore) Account(id string) (Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, a := range s.data.Accounts {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+
+	return Account{}, fmt.Errorf("The account %s could not be found.", id)
+}
+
+// Accounts returns all the accounts, in the order they were saved
+func (s *MemoryStore) Accounts() ([]Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]Account(nil), s.data.Accounts...), nil
+}
+
+// SaveAccount saves the given account, replacing any account with the
+// same ID
+func (s *MemoryStore) SaveAccount(a Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data = s.data.withAccount(a)
+	return nil
+}
+
+// Transactions returns all the transactions, in the order they were saved
+func (s *MemoryStore) Transactions() ([]Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]Transaction(nil), s.data.Transactions...), nil
+}
+
+// SaveTransaction saves the given transaction
+func (s *MemoryStore) SaveTransaction(t Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data = s.data.withTransaction(t)
+	return nil
+}
+
+// withAccount returns a copy of d containing the given account
+func (d storeData) withAccount(a Account) storeData {
+	accounts := append([]Account(nil), d.Accounts...)
+	for i, v := range accounts {
+		if v.ID == a.ID {
+			accounts[i] = a
+			return storeData{accounts, d.Transactions}
+		}
+	}
+
+	return storeData{append(accounts, a), d.Transactions}
+}
+
+// withTransaction returns a copy of d containing the given transaction
+func (d storeData) withTransaction(t Transaction) storeData {
+	ts := append([]Transaction(nil), d.Transactions...)
+	return storeData{d.Accounts, append(ts, t)}
+}
+
+// FileStore is a Store keeping its content in memory, and writing it to a
+// JSON file every time it changes. It is safe for concurrent use, but the
+// file should not be shared between several FileStores
+type FileStore struct {
+	MemoryStore
+	path string
+}
+
+// NewFileStore creates a new FileStore writing to the file at the given
+// path. If the file already exists, its content is loaded
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path: path,
+	}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &s.data); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// SaveAccount saves the given account, replacing any account with the
+// same ID. The store is not modified if the file could not be written
+func (s *FileStore) SaveAccount(a Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.write(s.data.withAccount(a))
+}
+
+// SaveTransaction saves the given transaction. The store is not modified
+// if the file could not be written
+func (s *FileStore) SaveTransaction(t Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.write(s.data.withTransaction(t))
+}
+
+// write writes the given data to the file of the store, and keeps it as the
+// new content of the store. The file is replaced atomically
+func (s *FileStore) write(d storeData) error {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+
+	s.data = d
+	return nil
+}
@@ -0,0 +1,78 @@
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+
+	if _, err := s.Account("cash"); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	s.SaveAccount(Account{ID: "cash", Name: "Cash"})
+	s.SaveAccount(Account{ID: "cash", Name: "Petty cash"})
+	a, err := s.Account("cash")
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if a.Name != "Petty cash" {
+		t.Error("The account should have been replaced")
+	}
+	if as, _ := s.Accounts(); len(as) != 1 {
+		t.Error("There should have been a single account")
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := os.MkdirTemp("", "ledger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ledger.json")
+
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+
+	l := New(s)
+	l.Open(Account{ID: "cash", Type: Asset})
+	l.Open(Account{ID: "sales", Type: Income})
+
+	tr := getTrader()
+	usd, _ := tr.NewAmountFromString("10", "usd")
+	tx := Transaction{}
+	tx.Debit("cash", usd)
+	tx.Credit("sales", usd)
+	if _, err := l.Post(tx); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+
+	s, err = NewFileStore(path)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if as, _ := s.Accounts(); len(as) != 2 {
+		t.Error("The accounts should have been loaded from the file")
+	}
+
+	b, err := New(s).Balance("cash")
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if b["USD"].StringFixed(2) != "10.00" {
+		t.Error("The transactions should have been loaded from the file")
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(path); err == nil {
+		t.Error("There should have been an error")
+	}
+}
@@ -0,0 +1,98 @@
+package ledger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+// Posting is a single entry of a Transaction on an Account. The value of
+// its Amount is positive for a debit, and negative for a credit
+type Posting struct {
+	Account string        `json:"account"`
+	Amount  trader.Amount `json:"amount"`
+}
+
+// IsDebit returns true if the Posting debits its Account
+func (p Posting) IsDebit() bool {
+	return p.Amount.Value.Cmp(decimal.New(0, 0)) > 0
+}
+
+// Transaction is a set of postings that must be balanced: for every
+// currency, the sum of the debits must be equal to the sum of the credits
+type Transaction struct {
+	ID          string    `json:"id"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Postings    []Posting `json:"postings"`
+	// Exchanges records the conversions linking the currencies of the
+	// Transaction, see Exchange
+	Exchanges []trader.Conversion `json:"exchanges,omitempty"`
+}
+
+// Debit adds a Posting debiting the given account of the Amount a
+func (t *Transaction) Debit(account string, a trader.Amount) {
+	t.Postings = append(t.Postings, Posting{
+		Account: account,
+		Amount:  a,
+	})
+}
+
+// Credit adds a Posting crediting the given account of the Amount a
+func (t *Transaction) Credit(account string, a trader.Amount) {
+	a.Value = decimal.New(0, 0).Sub(a.Value)
+	t.Postings = append(t.Postings, Posting{
+		Account: account,
+		Amount:  a,
+	})
+}
+
+// Exchange converts the Amount a to the given currency code using the
+// Trader of a, and adds the postings moving a out of the account from and
+// the converted amount into the account to. Both sides are balanced through
+// the trading account, so that the Transaction stays balanced in each
+// currency. The Conversion used is recorded in the Transaction
+func (t *Transaction) Exchange(from, to, trading string, a trader.Amount,
+	code trader.CurrencyCode) error {
+
+	n, c, err := a.ConvertWithTrace(code)
+	if err != nil {
+		return err
+	}
+
+	t.Credit(from, a)
+	t.Debit(trading, a)
+	t.Credit(trading, n)
+	t.Debit(to, n)
+	t.Exchanges = append(t.Exchanges, c)
+	return nil
+}
+
+// Balance returns the sum of the postings of the Transaction, for each
+// currency. It is empty if the Transaction is balanced
+func (t Transaction) Balance() trader.Bag {
+	b := trader.Bag{}
+	for _, p := range t.Postings {
+		b = b.Add(p.Amount)
+	}
+
+	return b
+}
+
+// Validate returns an error if the Transaction has no postings, or if it
+// is not balanced in one of its currencies
+func (t Transaction) Validate() error {
+	if len(t.Postings) == 0 {
+		return fmt.Errorf("The transaction %s has no postings.", t.ID)
+	}
+
+	b := t.Balance()
+	if codes := b.Codes(); len(codes) > 0 {
+		return fmt.Errorf("The transaction %s is not balanced in %s (off by %s).",
+			t.ID, codes[0], b[codes[0]])
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+func getTrader() trader.Trader {
+	c1, _ := trader.NewCurrency("USD", decimal.NewFromFloat(1))
+	c2, _ := trader.NewCurrency("EUR", decimal.NewFromFloat(0.8))
+	t, _ := trader.New(trader.Currencies{c1, c2}, "usd")
+	return t
+}
+
+func TestTransaction_Validate(t *testing.T) {
+	tr := getTrader()
+	usd, _ := tr.NewAmountFromString("10", "usd")
+	eur, _ := tr.NewAmountFromString("8", "eur")
+
+	tx := Transaction{ID: "1"}
+	if err := tx.Validate(); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	tx.Debit("cash", usd)
+	tx.Credit("sales", eur)
+	if err := tx.Validate(); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	tx = Transaction{ID: "2"}
+	tx.Debit("cash", usd)
+	tx.Credit("sales", usd)
+	if err := tx.Validate(); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !tx.Postings[0].IsDebit() || tx.Postings[1].IsDebit() {
+		t.Error("The postings sides were incorrectly set")
+	}
+}
+
+func TestTransaction_Exchange(t *testing.T) {
+	tr := getTrader()
+	usd, _ := tr.NewAmountFromString("10", "usd")
+
+	tx := Transaction{ID: "1"}
+	if err := tx.Exchange("cash-usd", "cash-eur", "fx", usd, "gel"); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	if err := tx.Exchange("cash-usd", "cash-eur", "fx", usd, "eur"); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if err := tx.Validate(); err != nil {
+		t.Error("The transaction should have been balanced")
+	}
+	if len(tx.Postings) != 4 {
+		t.Error("Four postings should have been added")
+	}
+	if len(tx.Exchanges) != 1 || tx.Exchanges[0].Rate.StringFixed(2) != "0.80" {
+		t.Error("The exchange rate should have been recorded")
+	}
+	if tx.Postings[3].Account != "cash-eur" ||
+		tx.Postings[3].Amount.String(2) != "8.00" {
+
+		t.Error("The converted amount was incorrectly posted")
+	}
+}
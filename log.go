@@ -0,0 +1,41 @@
+//go:build go1.21
+
+package trader
+
+import (
+	"log/slog"
+)
+
+// LogValue implements slog.LogValuer. The Amount is logged as a group of
+// its value, currency code and value in minor units, without its Trader
+func (a Amount) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("value", a.text()),
+		slog.String("currency", string(a.Currency.Code)),
+		slog.Int64("minor_units", a.minorUnits()),
+	)
+}
+
+// LogValue implements slog.LogValuer. The Currency is logged as a group of
+// its code and value relative to the base currency
+func (c Currency) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("code", string(c.Code)),
+		slog.String("value", c.Value.String()),
+	)
+}
+
+// LogValue implements slog.LogValuer. Only a summary of the Trader is
+// logged: its base currency, its number of rates and its snapshot ID, if
+// any, but never its rates
+func (t Trader) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("base_currency", string(t.BaseCurrency.Code)),
+		slog.Int("rates", len(t.Currencies)),
+	}
+	if t.SnapshotID != "" {
+		attrs = append(attrs, slog.String("snapshot_id", t.SnapshotID))
+	}
+
+	return slog.GroupValue(attrs...)
+}
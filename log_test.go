@@ -0,0 +1,51 @@
+//go:build go1.21
+
+package trader
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogValue(t *testing.T) {
+	trader := getTrader3()
+	amount, _ := trader.NewAmountFromString("12.3", "eur")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	logger.Info("amount", "amount", amount)
+	expected := "level=INFO msg=amount amount.value=12.30 amount.currency=EUR amount.minor_units=1230\n"
+	if s := buf.String(); s != expected {
+		t.Errorf("The amount was incorrectly logged: %s", s)
+	}
+
+	buf.Reset()
+	logger.Info("currency", "currency", amount.Currency)
+	expected = "level=INFO msg=currency currency.code=EUR currency.value=0.8\n"
+	if s := buf.String(); s != expected {
+		t.Errorf("The currency was incorrectly logged: %s", s)
+	}
+
+	buf.Reset()
+	logger.Info("trader", "trader", trader)
+	expected = "level=INFO msg=trader trader.base_currency=USD trader.rates=3 trader.snapshot_id=snapshot-1\n"
+	if s := buf.String(); s != expected {
+		t.Errorf("The trader was incorrectly logged: %s", s)
+	}
+
+	buf.Reset()
+	logger.Info("trader", "trader", getTrader())
+	if s := buf.String(); strings.Contains(s, "snapshot_id") || strings.Contains(s, "EUR") {
+		t.Errorf("The trader was incorrectly logged: %s", s)
+	}
+}
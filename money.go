@@ -0,0 +1,132 @@
+package trader
+
+import (
+	"github.com/processout/decimal"
+)
+
+// Money is a value in a given currency, without any Trader. It is a
+// lightweight alternative to Amount for same-currency arithmetic, which
+// never needs any rate
+type Money struct {
+	Value decimal.Decimal `json:"value"`
+	Code  CurrencyCode    `json:"currency"`
+}
+
+// NewMoney creates a new Money from a decimal and a currency code. An error
+// is returned if the currency code is not part of ISO 4217
+func NewMoney(d decimal.Decimal, code CurrencyCode) (Money, error) {
+	if !code.Verify() {
+		return Money{}, UnknownCurrencyError{code}
+	}
+
+	return Money{
+		Value: d,
+		Code:  code.format(),
+	}, nil
+}
+
+// NewMoneyFromString creates a new Money from a string and a currency code.
+// Returns an error if the string could not be parsed
+func NewMoneyFromString(s string, code CurrencyCode) (Money, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return NewMoney(d, code)
+}
+
+// Money returns the value and currency of the Amount as a Money
+func (a Amount) Money() Money {
+	return Money{
+		Value: a.Value,
+		Code:  a.Currency.Code,
+	}
+}
+
+// Amount creates an Amount from the Money, using the given Trader. An
+// error is returned if the currency is not supported by the Trader
+func (m Money) Amount(t *Trader) (Amount, error) {
+	if t == nil {
+		return emptyAmount, CurrencyNotFoundError{m.Code}
+	}
+
+	return t.NewAmount(m.Value, m.Code)
+}
+
+// SameCurrency returns true if m and n are in the same currency
+func (m Money) SameCurrency(n Money) bool {
+	return m.Code.format() == n.Code.format()
+}
+
+// Add returns the sum of m and n. If m and n are not in the same currency,
+// an error is returned
+func (m Money) Add(n Money) (Money, error) {
+	if !m.SameCurrency(n) {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	m.Value = m.Value.Add(n.Value)
+	return m, nil
+}
+
+// Sub returns the substraction of n from m. If m and n are not in the same
+// currency, an error is returned
+func (m Money) Sub(n Money) (Money, error) {
+	if !m.SameCurrency(n) {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	m.Value = m.Value.Sub(n.Value)
+	return m, nil
+}
+
+// Mul returns m multiplied by the given factor
+func (m Money) Mul(d decimal.Decimal) Money {
+	m.Value = m.Value.Mul(d)
+	return m
+}
+
+// Neg returns the opposite of m
+func (m Money) Neg() Money {
+	m.Value = decimal.New(0, 0).Sub(m.Value)
+	return m
+}
+
+// Cmp compares m and n like Amount.Cmp does. If m and n are not in the same
+// currency, an error is returned
+func (m Money) Cmp(n Money) (int, error) {
+	if !m.SameCurrency(n) {
+		return 0, ErrCurrencyMismatch
+	}
+
+	return m.Value.Cmp(n.Value), nil
+}
+
+// Equal returns true if m and n are in the same currency and have the same
+// value
+func (m Money) Equal(n Money) bool {
+	r, err := m.Cmp(n)
+	return err == nil && r == 0
+}
+
+// IsZero returns true if the value of m is zero
+func (m Money) IsZero() bool {
+	return m.Value.Cmp(decimal.New(0, 0)) == 0
+}
+
+// Round returns m rounded to the decimal places of its currency, using the
+// given RoundingMode
+func (m Money) Round(mode RoundingMode) Money {
+	if info := m.Code.Information(); info != nil {
+		m.Value = mode.Round(m.Value, int32(info.Places))
+	}
+
+	return m
+}
+
+// String returns the Money in its canonical form, e.g. "USD 12.34"
+func (m Money) String() string {
+	a := Amount{Value: m.Value, Currency: Currency{Code: m.Code}}
+	return string(m.Code) + " " + a.text()
+}
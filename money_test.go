@@ -0,0 +1,93 @@
+package trader
+
+import (
+	"testing"
+
+	"github.com/processout/decimal"
+)
+
+func TestNewMoney(t *testing.T) {
+	m, err := NewMoneyFromString("12.3", "usd")
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if m.Code != "USD" || m.String() != "USD 12.30" {
+		t.Errorf("The money was incorrectly created: %s", m)
+	}
+
+	if _, err := NewMoney(decimal.New(1, 0), "lel"); err == nil {
+		t.Error("There should have been an error")
+	}
+	if _, err := NewMoneyFromString("twelve", "usd"); err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	m1, _ := NewMoneyFromString("12.3", "usd")
+	m2, _ := NewMoneyFromString("0.7", "usd")
+	m3, _ := NewMoneyFromString("0.7", "eur")
+
+	sum, err := m1.Add(m2)
+	if err != nil || sum.String() != "USD 13.00" {
+		t.Errorf("The sum was incorrectly computed: %s", sum)
+	}
+	diff, err := m1.Sub(m2)
+	if err != nil || diff.String() != "USD 11.60" {
+		t.Errorf("The difference was incorrectly computed: %s", diff)
+	}
+	if r, err := m1.Cmp(m2); err != nil || r <= 0 {
+		t.Error("Answer should have been positive (12.3 > 0.7)")
+	}
+	if p := m2.Mul(decimal.NewFromFloat(3)); !p.Equal(Money{Value: decimal.NewFromFloat(2.1), Code: "USD"}) {
+		t.Errorf("The product was incorrectly computed: %s", p)
+	}
+	if z, _ := m1.Sub(m1); !z.IsZero() {
+		t.Error("The difference should have been zero")
+	}
+	if n := m2.Neg(); n.String() != "USD -0.70" {
+		t.Errorf("The opposite was incorrectly computed: %s", n)
+	}
+
+	if _, err := m1.Add(m3); err != ErrCurrencyMismatch {
+		t.Error("There should have been an error")
+	}
+	if _, err := m1.Sub(m3); err != ErrCurrencyMismatch {
+		t.Error("There should have been an error")
+	}
+	if _, err := m1.Cmp(m3); err != ErrCurrencyMismatch {
+		t.Error("There should have been an error")
+	}
+	if m2.Equal(m3) {
+		t.Error("Money in different currencies shouldn't be equal")
+	}
+
+	jpy, _ := NewMoneyFromString("12.5", "jpy")
+	if r := jpy.Round(RoundHalfEven); r.String() != "JPY 12" {
+		t.Errorf("The money was incorrectly rounded: %s", r)
+	}
+}
+
+func TestMoney_Amount(t *testing.T) {
+	trader := getTrader()
+	m, _ := NewMoneyFromString("8", "eur")
+
+	a, err := m.Amount(&trader)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !a.Trader.Is(trader) || !a.Currency.Value.Equals(decimal.NewFromFloat(0.8)) {
+		t.Error("The amount should have referenced the trader")
+	}
+	if !a.Money().Equal(m) {
+		t.Error("The money should have been kept")
+	}
+
+	gbp, _ := NewMoneyFromString("8", "gbp")
+	if _, err := gbp.Amount(&trader); err == nil {
+		t.Error("There should have been an error")
+	}
+	if _, err := m.Amount(nil); err == nil {
+		t.Error("There should have been an error")
+	}
+}
@@ -0,0 +1,193 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider provides snapshots of rates, from which Traders are created
+type Provider interface {
+	// Fetch fetches the current Snapshot of rates
+	Fetch(ctx context.Context) (Snapshot, error)
+}
+
+// ProviderFunc is an adapter to use a function as a Provider
+type ProviderFunc func(ctx context.Context) (Snapshot, error)
+
+// Fetch calls f(ctx)
+func (f ProviderFunc) Fetch(ctx context.Context) (Snapshot, error) {
+	return f(ctx)
+}
+
+// FetchTrader fetches a Snapshot from the given Provider, and creates a
+// Trader from its rates
+func FetchTrader(ctx context.Context, p Provider) (Trader, error) {
+	s, err := p.Fetch(ctx)
+	if err != nil {
+		return emptyTrader, err
+	}
+
+	return s.Trader()
+}
+
+// FallbackProvider is a Provider trying each of its providers in order,
+// until one of them succeeds
+type FallbackProvider []Provider
+
+// Fetch returns the Snapshot of the first provider that succeeds. If they
+// all fail, an error listing all of their errors is returned
+func (p FallbackProvider) Fetch(ctx context.Context) (Snapshot, error) {
+	if len(p) == 0 {
+		return Snapshot{}, errors.New("The fallback provider has no providers.")
+	}
+
+	msgs := make([]string, 0, len(p))
+	for i, provider := range p {
+		s, err := provider.Fetch(ctx)
+		if err == nil {
+			return s, nil
+		}
+		msgs = append(msgs, fmt.Sprintf("provider %d: %s", i, err))
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return Snapshot{}, fmt.Errorf("All the providers failed: %s", strings.Join(msgs, "; "))
+}
+
+// CachingProvider is a Provider caching the snapshots of another Provider.
+// A cached Snapshot is fresh during TTL, and is returned directly. It is
+// then stale during StaleWhileRevalidate: it is still returned directly,
+// but a new Snapshot is fetched in the background. Past that, a new
+// Snapshot is fetched before returning. It is safe for concurrent use
+type CachingProvider struct {
+	Provider             Provider
+	TTL                  time.Duration
+	StaleWhileRevalidate time.Duration
+	// Now returns the current time. It defaults to time.Now
+	Now func() time.Time
+	// OnError, if not nil, receives the errors of the background fetches
+	OnError func(error)
+
+	mu         sync.Mutex
+	snapshot   Snapshot
+	cachedAt   time.Time
+	cached     bool
+	refreshing bool
+}
+
+// NewCachingProvider creates a new CachingProvider caching the snapshots of
+// p with the given durations
+func NewCachingProvider(p Provider, ttl, staleWhileRevalidate time.Duration) *CachingProvider {
+	return &CachingProvider{
+		Provider:             p,
+		TTL:                  ttl,
+		StaleWhileRevalidate: staleWhileRevalidate,
+	}
+}
+
+// now returns the current time of the clock of the provider
+func (p *CachingProvider) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+
+	return time.Now()
+}
+
+// Fetch returns the cached Snapshot if it is fresh or stale, or fetches a
+// new one otherwise
+func (p *CachingProvider) Fetch(ctx context.Context) (Snapshot, error) {
+	p.mu.Lock()
+	if p.cached {
+		age := p.now().Sub(p.cachedAt)
+		if age < p.TTL {
+			defer p.mu.Unlock()
+			return p.snapshot, nil
+		}
+
+		if age < p.TTL+p.StaleWhileRevalidate {
+			defer p.mu.Unlock()
+			if !p.refreshing {
+				p.refreshing = true
+				go p.revalidate()
+			}
+			return p.snapshot, nil
+		}
+	}
+	p.mu.Unlock()
+
+	s, err := p.Provider.Fetch(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	p.store(s)
+	return s, nil
+}
+
+// revalidate fetches a new Snapshot in the background
+func (p *CachingProvider) revalidate() {
+	s, err := p.Provider.Fetch(context.Background())
+
+	p.mu.Lock()
+	p.refreshing = false
+	p.mu.Unlock()
+
+	if err != nil {
+		if p.OnError != nil {
+			p.OnError(err)
+		}
+		return
+	}
+	p.store(s)
+}
+
+// store caches the given Snapshot
+func (p *CachingProvider) store(s Snapshot) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.snapshot = s
+	p.cachedAt = p.now()
+	p.cached = true
+}
+
+// StaleGuard is a Provider refusing the snapshots of another Provider that
+// were fetched more than MaxAge ago, or whose ValidUntil time has passed
+type StaleGuard struct {
+	Provider Provider
+	MaxAge   time.Duration
+	// Now returns the current time. It defaults to time.Now
+	Now func() time.Time
+}
+
+// Fetch returns the Snapshot of the guarded Provider, or an error if it is
+// too old
+func (g StaleGuard) Fetch(ctx context.Context) (Snapshot, error) {
+	s, err := g.Provider.Fetch(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	now := time.Now()
+	if g.Now != nil {
+		now = g.Now()
+	}
+
+	if age := now.Sub(s.FetchedAt); age > g.MaxAge {
+		return Snapshot{}, fmt.Errorf("The snapshot fetched at %s is too old (%s > %s).",
+			s.FetchedAt.Format(time.RFC3339), age, g.MaxAge)
+	}
+	if !s.ValidUntil.IsZero() && now.After(s.ValidUntil) {
+		return Snapshot{}, fmt.Errorf("The snapshot was only valid until %s.",
+			s.ValidUntil.Format(time.RFC3339))
+	}
+
+	return s, nil
+}
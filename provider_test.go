@@ -0,0 +1,205 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/processout/decimal"
+)
+
+// fakeClock is a clock whose time only changes when advanced
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2016, 9, 23, 15, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// fakeProvider is a Provider returning snapshots whose source is the number
+// of the fetch, or err if set
+type fakeProvider struct {
+	mu      sync.Mutex
+	clock   *fakeClock
+	err     error
+	fetches int
+	fetched chan struct{}
+}
+
+func (p *fakeProvider) Fetch(ctx context.Context) (Snapshot, error) {
+	p.mu.Lock()
+	fetched := p.fetched
+	defer func() {
+		p.mu.Unlock()
+		if fetched != nil {
+			fetched <- struct{}{}
+		}
+	}()
+
+	p.fetches++
+	if p.err != nil {
+		return Snapshot{}, p.err
+	}
+
+	usd, _ := NewCurrency("USD", decimal.New(1, 0))
+	trader, _ := New(Currencies{usd}, "usd")
+	now := p.clock.Now()
+	return NewSnapshot(trader, string(rune('0'+p.fetches)), now, now.Add(time.Hour)), nil
+}
+
+func (p *fakeProvider) Fetches() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.fetches
+}
+
+func TestFetchTrader(t *testing.T) {
+	p := &fakeProvider{clock: newFakeClock()}
+
+	trader, err := FetchTrader(context.Background(), p)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !trader.BaseCurrency.Is("usd") || trader.SnapshotID == "" {
+		t.Error("The trader was incorrectly created")
+	}
+
+	p.err = errors.New("down")
+	if _, err := FetchTrader(context.Background(), p); err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestFallbackProvider(t *testing.T) {
+	clock := newFakeClock()
+	down := &fakeProvider{clock: clock, err: errors.New("down")}
+	up := &fakeProvider{clock: clock}
+	unused := &fakeProvider{clock: clock}
+
+	if _, err := (FallbackProvider{}).Fetch(context.Background()); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	s, err := FallbackProvider{down, up, unused}.Fetch(context.Background())
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if s.Source != "1" || down.Fetches() != 1 || unused.Fetches() != 0 {
+		t.Error("The providers should have been tried in order")
+	}
+
+	_, err = FallbackProvider{down, down}.Fetch(context.Background())
+	if err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestCachingProvider(t *testing.T) {
+	clock := newFakeClock()
+	p := &fakeProvider{clock: clock}
+	c := NewCachingProvider(p, time.Minute, time.Minute)
+	c.Now = clock.Now
+
+	s, err := c.Fetch(context.Background())
+	if err != nil || s.Source != "1" {
+		t.Error("The snapshot should have been fetched")
+	}
+
+	// Fresh
+	clock.Advance(30 * time.Second)
+	s, _ = c.Fetch(context.Background())
+	if s.Source != "1" || p.Fetches() != 1 {
+		t.Error("The cached snapshot should have been returned")
+	}
+
+	// Stale: returned directly, and revalidated in the background
+	p.mu.Lock()
+	p.fetched = make(chan struct{}, 10)
+	p.mu.Unlock()
+	clock.Advance(time.Minute)
+	s, _ = c.Fetch(context.Background())
+	if s.Source != "1" {
+		t.Error("The stale snapshot should have been returned")
+	}
+	select {
+	case <-p.fetched:
+	case <-time.After(5 * time.Second):
+		t.Fatal("The snapshot should have been revalidated")
+	}
+
+	waitFor(t, func() bool {
+		s, _ := c.Fetch(context.Background())
+		return s.Source == "2"
+	})
+
+	// Expired: fetched synchronously
+	clock.Advance(3 * time.Minute)
+	s, _ = c.Fetch(context.Background())
+	if s.Source != "3" {
+		t.Error("A new snapshot should have been fetched")
+	}
+
+	clock.Advance(3 * time.Minute)
+	p.mu.Lock()
+	p.err = errors.New("down")
+	p.mu.Unlock()
+	if _, err := c.Fetch(context.Background()); err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestStaleGuard(t *testing.T) {
+	clock := newFakeClock()
+	p := &fakeProvider{clock: clock}
+	c := NewCachingProvider(p, 2*time.Hour, 0)
+	c.Now = clock.Now
+	g := StaleGuard{Provider: c, MaxAge: 10 * time.Minute, Now: clock.Now}
+
+	if _, err := g.Fetch(context.Background()); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+
+	clock.Advance(20 * time.Minute)
+	if _, err := g.Fetch(context.Background()); err == nil {
+		t.Error("The old snapshot should have been refused")
+	}
+
+	g.MaxAge = 2 * time.Hour
+	clock.Advance(time.Hour)
+	if _, err := g.Fetch(context.Background()); err == nil {
+		t.Error("The expired snapshot should have been refused")
+	}
+
+	p.err = errors.New("down")
+	g.Provider = p
+	if _, err := g.Fetch(context.Background()); err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+// waitFor waits until the given condition is true
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("The condition was never met")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,275 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/processout/decimal"
+)
+
+// SharedTrader holds a Trader that can be replaced while it is being used
+// concurrently. It is safe for concurrent use
+type SharedTrader struct {
+	mu     sync.RWMutex
+	trader Trader
+}
+
+// NewSharedTrader creates a new SharedTrader holding the given Trader
+func NewSharedTrader(t Trader) *SharedTrader {
+	return &SharedTrader{
+		trader: t,
+	}
+}
+
+// Load returns the Trader currently held
+func (s *SharedTrader) Load() Trader {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.trader
+}
+
+// Store replaces the Trader held
+func (s *SharedTrader) Store(t Trader) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.trader = t
+}
+
+// RateUpdate describes the rates that changed when a Refresher swapped the
+// Trader
+type RateUpdate struct {
+	// Trader is the new Trader
+	Trader Trader
+	// Changes are the rates that changed or were removed, relative to the
+	// base currency of the previous Trader
+	Changes []RateMove
+	// Added are the currencies that were not supported by the previous
+	// Trader
+	Added Currencies
+}
+
+// Refresher periodically fetches new rates from a Provider, validates
+// them, swaps them into a SharedTrader, and notifies its subscribers of the
+// rates that changed. Its fields must not be modified once Run was called
+type Refresher struct {
+	Provider Provider
+	Trader   *SharedTrader
+	// Interval is the interval between two refreshes
+	Interval time.Duration
+	// Jitter is the fraction of the interval by which each wait is randomly
+	// shortened or lengthened, e.g. 0.1 for ±10%
+	Jitter float64
+	// MinBackoff is the wait after a first failed refresh. It is doubled
+	// after each consecutive failure, up to MaxBackoff. They default to a
+	// tenth of the interval and to the interval
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxMove, if positive, is the percentage by which a rate may move
+	// between two refreshes. New rates moving more are refused
+	MaxMove decimal.Decimal
+	// OnError, if not nil, receives the errors of the refreshes
+	OnError func(error)
+
+	// After waits for the given duration. It defaults to time.After
+	After func(time.Duration) <-chan time.Time
+	// Rand returns a random number in [0, 1) used for the jitter. It
+	// defaults to math/rand.Float64
+	Rand func() float64
+
+	mu        sync.Mutex
+	callbacks []func(RateUpdate)
+	channels  []chan RateUpdate
+	failures  int
+}
+
+// NewRefresher creates a new Refresher fetching rates from p every interval
+// and swapping them into t. Failed refreshes are retried after a backoff
+// starting at a tenth of the interval, up to the interval
+func NewRefresher(p Provider, t *SharedTrader, interval time.Duration) *Refresher {
+	return &Refresher{
+		Provider:   p,
+		Trader:     t,
+		Interval:   interval,
+		Jitter:     0.1,
+		MinBackoff: interval / 10,
+		MaxBackoff: interval,
+	}
+}
+
+// Subscribe registers a callback called with every RateUpdate. Callbacks
+// are called synchronously by the refresher
+func (r *Refresher) Subscribe(f func(RateUpdate)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.callbacks = append(r.callbacks, f)
+}
+
+// Updates returns a channel receiving every RateUpdate. Updates are dropped
+// if the channel buffer is full. The channel is closed when Run returns
+func (r *Refresher) Updates(buffer int) <-chan RateUpdate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := make(chan RateUpdate, buffer)
+	r.channels = append(r.channels, c)
+	return c
+}
+
+// Refresh fetches new rates, validates them and swaps them into the
+// SharedTrader. The subscribers are notified if any rate changed. An error
+// is returned, and the current Trader kept, if the rates could not be
+// fetched or are invalid
+func (r *Refresher) Refresh(ctx context.Context) error {
+	t, err := FetchTrader(ctx, r.Provider)
+	if err != nil {
+		return err
+	}
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	zero := decimal.New(0, 0)
+	prev := r.Trader.Load()
+	u := RateUpdate{
+		Trader: t,
+	}
+	if prev.BaseCurrency.Code == "" {
+		u.Added = t.Currencies
+	} else {
+		moves, err := compareRates(prev.Currencies, prev.BaseCurrency.Code,
+			t.Currencies, zero)
+		if err != nil {
+			return err
+		}
+		for _, m := range moves {
+			if !m.Added {
+				u.Changes = append(u.Changes, m)
+				continue
+			}
+
+			c, _ := t.Currencies.Find(m.Code)
+			u.Added = append(u.Added, c)
+		}
+	}
+
+	if r.MaxMove.Cmp(zero) > 0 {
+		for _, m := range u.Changes {
+			change := m.Change
+			if change.Cmp(zero) < 0 {
+				change = zero.Sub(change)
+			}
+			if !m.Removed && change.Cmp(r.MaxMove) > 0 {
+				return fmt.Errorf("The rate of %s moved by %s%%, more than %s%%.",
+					m.Code, m.Change.StringFixed(2), r.MaxMove)
+			}
+		}
+	}
+
+	r.Trader.Store(t)
+	if len(u.Changes) > 0 || len(u.Added) > 0 {
+		r.notify(u)
+	}
+	return nil
+}
+
+// notify sends the RateUpdate to the subscribers
+func (r *Refresher) notify(u RateUpdate) {
+	r.mu.Lock()
+	callbacks := make([]func(RateUpdate), len(r.callbacks))
+	copy(callbacks, r.callbacks)
+	channels := make([]chan RateUpdate, len(r.channels))
+	copy(channels, r.channels)
+	r.mu.Unlock()
+
+	for _, f := range callbacks {
+		f(u)
+	}
+	for _, c := range channels {
+		select {
+		case c <- u:
+		default:
+		}
+	}
+}
+
+// Run refreshes the rates right away, and then every interval, until the
+// context is done. It always returns the error of the context
+func (r *Refresher) Run(ctx context.Context) error {
+	defer func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		for _, c := range r.channels {
+			close(c)
+		}
+		r.channels = nil
+	}()
+
+	after := r.After
+	if after == nil {
+		after = time.After
+	}
+
+	for {
+		err := r.Refresh(ctx)
+		if err != nil && ctx.Err() == nil && r.OnError != nil {
+			r.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-after(r.wait(err)):
+		}
+	}
+}
+
+// minWait is the shortest wait between two refreshes, so that a Refresher
+// with no interval or backoff doesn't call its Provider in a tight loop
+const minWait = 100 * time.Millisecond
+
+// wait returns the duration to wait after a refresh that returned err,
+// with the jitter applied. It is never shorter than minWait
+func (r *Refresher) wait(err error) time.Duration {
+	d := r.Interval
+	if err != nil {
+		min, max := r.MinBackoff, r.MaxBackoff
+		if min <= 0 {
+			min = r.Interval / 10
+		}
+		if max <= 0 {
+			max = r.Interval
+		}
+		if min < minWait {
+			min = minWait
+		}
+
+		r.failures++
+		d = min
+		for i := 1; i < r.failures && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+	} else {
+		r.failures = 0
+	}
+
+	random := r.Rand
+	if random == nil {
+		random = rand.Float64
+	}
+
+	d = time.Duration(float64(d) * (1 + r.Jitter*(2*random()-1)))
+	if d < minWait {
+		d = minWait
+	}
+	return d
+}
@@ -0,0 +1,175 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/processout/decimal"
+)
+
+// queueProvider is a Provider returning the rates of EUR from a queue, or
+// an error for the empty values
+type queueProvider struct {
+	eur []string
+}
+
+func (p *queueProvider) Fetch(ctx context.Context) (Snapshot, error) {
+	if len(p.eur) == 0 {
+		return Snapshot{}, errors.New("empty queue")
+	}
+	v := p.eur[0]
+	p.eur = p.eur[1:]
+	if v == "" {
+		return Snapshot{}, errors.New("down")
+	}
+
+	usd, _ := NewCurrency("USD", decimal.New(1, 0))
+	value, _ := decimal.NewFromString(v)
+	eur, _ := NewCurrency("EUR", value)
+	trader, _ := New(Currencies{usd, eur}, "usd")
+	return NewSnapshot(trader, "queue", time.Now(), time.Time{}), nil
+}
+
+func TestRefresher_Refresh(t *testing.T) {
+	shared := NewSharedTrader(Trader{})
+	r := NewRefresher(&queueProvider{eur: []string{"0.8", "0.8", "0.82", "0", "0.9", ""}},
+		shared, time.Minute)
+	r.MaxMove = decimal.NewFromFloat(5)
+
+	var updates []RateUpdate
+	r.Subscribe(func(u RateUpdate) {
+		updates = append(updates, u)
+	})
+
+	if err := r.Refresh(context.Background()); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if len(updates) != 1 || len(updates[0].Added) != 2 || len(updates[0].Changes) != 0 {
+		t.Errorf("The initial rates should have been notified as added: %+v", updates)
+	}
+	if !shared.Load().BaseCurrency.Is("usd") {
+		t.Error("The trader should have been swapped")
+	}
+
+	r.Refresh(context.Background())
+	if len(updates) != 1 {
+		t.Error("No update should have been notified for unchanged rates")
+	}
+
+	r.Refresh(context.Background())
+	if len(updates) != 2 || len(updates[1].Changes) != 1 ||
+		updates[1].Changes[0].New.String() != "0.82" {
+
+		t.Errorf("The changed rate should have been notified: %+v", updates)
+	}
+
+	if err := r.Refresh(context.Background()); err == nil {
+		t.Error("The invalid rates should have been refused")
+	}
+	if err := r.Refresh(context.Background()); err == nil {
+		t.Error("The rates moving too much should have been refused")
+	}
+	if err := r.Refresh(context.Background()); err == nil {
+		t.Error("There should have been an error")
+	}
+	if c, _ := shared.Load().Currencies.Find("eur"); c.Value.String() != "0.82" {
+		t.Error("The last valid trader should have been kept")
+	}
+}
+
+func TestRefresher_Run(t *testing.T) {
+	shared := NewSharedTrader(Trader{})
+	r := NewRefresher(&queueProvider{eur: []string{"0.8", "", "", "", "0.81"}},
+		shared, time.Minute)
+	r.MinBackoff = time.Second
+	r.MaxBackoff = 3 * time.Second
+	r.Rand = func() float64 { return 1 }
+
+	waits := make(chan time.Duration)
+	ticks := make(chan time.Time)
+	r.After = func(d time.Duration) <-chan time.Time {
+		waits <- d
+		return ticks
+	}
+	var errs []error
+	r.OnError = func(err error) {
+		errs = append(errs, err)
+	}
+	updates := r.Updates(10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- r.Run(ctx)
+	}()
+
+	// The jitter lengthens every wait by 10%
+	expected := []time.Duration{
+		66 * time.Second,
+		1100 * time.Millisecond,
+		2200 * time.Millisecond,
+		3300 * time.Millisecond,
+		66 * time.Second,
+	}
+	for i, e := range expected {
+		if d := <-waits; d != e {
+			t.Errorf("Wait %d should have been %s, got %s", i, e, d)
+		}
+		if i < len(expected)-1 {
+			ticks <- time.Now()
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Error("The context error should have been returned")
+	}
+	if len(errs) != 3 {
+		t.Errorf("The errors should have been reported: %v", errs)
+	}
+
+	u1, ok1 := <-updates
+	u2, ok2 := <-updates
+	_, ok3 := <-updates
+	if !ok1 || !ok2 || len(u1.Added) != 2 || len(u2.Changes) != 1 {
+		t.Error("The updates should have been sent on the channel")
+	}
+	if ok3 {
+		t.Error("The channel should have been closed")
+	}
+}
+
+func TestSharedTrader(t *testing.T) {
+	s := NewSharedTrader(getTrader())
+	if !s.Load().Is(getTrader()) {
+		t.Error("The trader should have been loaded")
+	}
+
+	s.Store(getTrader2())
+	if !s.Load().Is(getTrader2()) {
+		t.Error("The trader should have been replaced")
+	}
+}
+
+func TestRefresher_wait(t *testing.T) {
+	r := &Refresher{Interval: time.Second}
+	if d := r.wait(errors.New("fail")); d != 100*time.Millisecond {
+		t.Errorf("The backoff should have defaulted to a tenth of the interval, got %s", d)
+	}
+	for i := 0; i < 5; i++ {
+		r.wait(errors.New("fail"))
+	}
+	if d := r.wait(errors.New("fail")); d != time.Second {
+		t.Errorf("The backoff should have been capped by the interval, got %s", d)
+	}
+
+	r = &Refresher{}
+	if d := r.wait(nil); d != minWait {
+		t.Errorf("The wait should have been at least %s, got %s", minWait, d)
+	}
+	if d := r.wait(errors.New("fail")); d != minWait {
+		t.Errorf("The backoff should have been at least %s, got %s", minWait, d)
+	}
+}
@@ -0,0 +1,241 @@
+// Package revaluation computes the foreign exchange gains and losses of
+// balances held in foreign currencies, relative to a functional currency.
+// Unrealized gains come from revaluing open lots at a closing rate, while
+// realized gains come from settling lots.
+package revaluation
+
+import (
+	"fmt"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+// Lot is an amount held in a foreign currency, booked at a given rate
+type Lot struct {
+	ID string `json:"id"`
+	// Amount is the amount held, in the foreign currency
+	Amount trader.Amount `json:"amount"`
+	// Rate is the rate the Amount was booked at, to convert it to the
+	// functional currency
+	Rate decimal.Decimal `json:"rate"`
+}
+
+// Booked returns the value of the Lot in the functional currency, at its
+// booking rate
+func (l Lot) Booked() decimal.Decimal {
+	return l.Amount.Value.Mul(l.Rate)
+}
+
+// Revaluation is the revaluation of a Lot at a closing rate
+type Revaluation struct {
+	Lot Lot `json:"lot"`
+	// Rate is the closing rate the Lot was revalued at
+	Rate decimal.Decimal `json:"rate"`
+	// Booked is the value of the Lot at its booking rate
+	Booked trader.Amount `json:"booked"`
+	// Revalued is the value of the Lot at the closing rate
+	Revalued trader.Amount `json:"revalued"`
+	// Gain is the unrealized gain, negative for a loss
+	Gain trader.Amount `json:"gain"`
+}
+
+// Unrealized revalues each of the given lots with the rates of the closing
+// Trader, and returns the unrealized gains and losses in the functional
+// currency, along with their total. An error is returned if the currency
+// of a lot is not supported by the closing Trader, or if the arithmetic of
+// the closing Trader refuses one of the values
+func Unrealized(lots []Lot, closing *trader.Trader,
+	functional trader.CurrencyCode) ([]Revaluation, trader.Amount, error) {
+
+	if closing == nil {
+		return nil, trader.Amount{}, trader.CurrencyNotFoundError{Code: functional}
+	}
+	total, err := closing.NewAmount(decimal.New(0, 0), functional)
+	if err != nil {
+		return nil, trader.Amount{}, err
+	}
+
+	rs := make([]Revaluation, 0, len(lots))
+	for _, l := range lots {
+		a, err := closing.NewAmount(l.Amount.Value, l.Amount.Currency.Code)
+		if err != nil {
+			return nil, trader.Amount{}, err
+		}
+		rate, err := a.RateTo(functional)
+		if err != nil {
+			return nil, trader.Amount{}, err
+		}
+
+		r := Revaluation{
+			Lot:  l,
+			Rate: rate,
+		}
+		if r.Booked, err = closing.NewAmount(l.Booked(), functional); err != nil {
+			return nil, trader.Amount{}, err
+		}
+		if r.Revalued, err = closing.NewAmount(a.Value.Mul(rate), functional); err != nil {
+			return nil, trader.Amount{}, err
+		}
+		if r.Gain, err = r.Revalued.Sub(r.Booked); err != nil {
+			return nil, trader.Amount{}, err
+		}
+		if total, err = total.Add(r.Gain); err != nil {
+			return nil, trader.Amount{}, err
+		}
+
+		rs = append(rs, r)
+	}
+
+	return rs, total, nil
+}
+
+// Method is the method used to choose the cost of the settled lots
+type Method int
+
+const (
+	// FIFO settles the oldest lots first, at their own booking rate
+	FIFO Method = iota
+	// AverageCost settles lots at the average booking rate of all the lots
+	// of the currency, weighted by their amounts
+	AverageCost
+)
+
+// Settlement is the result of the settlement of lots
+type Settlement struct {
+	// Amount is the settled amount, in the foreign currency
+	Amount trader.Amount `json:"amount"`
+	// Rate is the rate the amount was settled at
+	Rate decimal.Decimal `json:"rate"`
+	// Cost is the value of the settled lots at their booking rates
+	Cost trader.Amount `json:"cost"`
+	// Proceeds is the value of the settled amount at the settlement rate
+	Proceeds trader.Amount `json:"proceeds"`
+	// Gain is the realized gain, negative for a loss
+	Gain trader.Amount `json:"gain"`
+	// Remaining is the list of the lots left after the settlement
+	Remaining []Lot `json:"remaining"`
+}
+
+// Settle settles the Amount a from the given lots, using the given Method,
+// and returns the realized gain in the functional currency. The settlement
+// rate is given by the Trader of a. An error is returned if the lots in the
+// currency of a don't hold enough to settle it, or if a is not positive
+func Settle(lots []Lot, a trader.Amount, functional trader.CurrencyCode,
+	method Method) (Settlement, error) {
+
+	if a.Value.Cmp(decimal.New(0, 0)) <= 0 {
+		return Settlement{}, fmt.Errorf("The amount %s to settle is not positive.", a.Value)
+	}
+
+	rate, err := a.RateTo(functional)
+	if err != nil {
+		return Settlement{}, err
+	}
+
+	var cost decimal.Decimal
+	var remaining []Lot
+	switch method {
+	case FIFO:
+		cost, remaining, err = settleFIFO(lots, a)
+	case AverageCost:
+		cost, remaining, err = settleAverage(lots, a)
+	default:
+		err = fmt.Errorf("The settlement method %d is not supported.", method)
+	}
+	if err != nil {
+		return Settlement{}, err
+	}
+
+	s := Settlement{
+		Amount:    a,
+		Rate:      rate,
+		Remaining: remaining,
+	}
+	if s.Cost, err = a.Trader.NewAmount(cost, functional); err != nil {
+		return Settlement{}, err
+	}
+	if s.Proceeds, err = a.Trader.NewAmount(a.Value.Mul(rate), functional); err != nil {
+		return Settlement{}, err
+	}
+	if s.Gain, err = s.Proceeds.Sub(s.Cost); err != nil {
+		return Settlement{}, err
+	}
+
+	return s, nil
+}
+
+// settleFIFO consumes the lots in the currency of a in order, and returns
+// the cost of the consumed amounts along with the remaining lots
+func settleFIFO(lots []Lot, a trader.Amount) (decimal.Decimal, []Lot, error) {
+	left := a.Value
+	cost := decimal.New(0, 0)
+	zero := decimal.New(0, 0)
+
+	remaining := make([]Lot, 0, len(lots))
+	for _, l := range lots {
+		if !l.Amount.Currency.Is(a.Currency.Code) || left.Cmp(zero) <= 0 {
+			remaining = append(remaining, l)
+			continue
+		}
+
+		if l.Amount.Value.Cmp(left) <= 0 {
+			cost = cost.Add(l.Booked())
+			left = left.Sub(l.Amount.Value)
+			continue
+		}
+
+		cost = cost.Add(left.Mul(l.Rate))
+		l.Amount.Value = l.Amount.Value.Sub(left)
+		left = zero
+		remaining = append(remaining, l)
+	}
+
+	if left.Cmp(zero) > 0 {
+		return zero, nil, fmt.Errorf("The lots in %s are short of %s to settle %s.",
+			a.Currency.Code, left, a.Value)
+	}
+
+	return cost, remaining, nil
+}
+
+// settleAverage merges the lots in the currency of a into a single lot at
+// their average rate, and consumes a from it. It returns the cost of a along
+// with the remaining lots
+func settleAverage(lots []Lot, a trader.Amount) (decimal.Decimal, []Lot, error) {
+	zero := decimal.New(0, 0)
+	held := zero
+	booked := zero
+
+	var pool *Lot
+	remaining := make([]Lot, 0, len(lots))
+	for _, l := range lots {
+		if !l.Amount.Currency.Is(a.Currency.Code) {
+			remaining = append(remaining, l)
+			continue
+		}
+
+		held = held.Add(l.Amount.Value)
+		booked = booked.Add(l.Booked())
+		if pool == nil {
+			first := l
+			pool = &first
+		}
+	}
+
+	if pool == nil || held.Cmp(zero) <= 0 || held.Cmp(a.Value) < 0 {
+		return zero, nil, fmt.Errorf("The lots in %s are short of %s to settle %s.",
+			a.Currency.Code, a.Value.Sub(held), a.Value)
+	}
+
+	if held.Cmp(a.Value) == 0 {
+		return booked, remaining, nil
+	}
+
+	// The cost is divided last, so that it is only rounded once
+	pool.Amount.Value = held.Sub(a.Value)
+	pool.Rate = booked.Div(held)
+	remaining = append(remaining, *pool)
+
+	return a.Value.Mul(booked).Div(held), remaining, nil
+}
@@ -0,0 +1,174 @@
+package revaluation
+
+import (
+	"testing"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+	"gopkg.in/processout/trader.v2/arith"
+)
+
+// getTrader returns a Trader in which 1 USD is worth the given value of EUR
+func getTrader(eur string) trader.Trader {
+	v, _ := decimal.NewFromString(eur)
+	c1, _ := trader.NewCurrency("USD", decimal.NewFromFloat(1))
+	c2, _ := trader.NewCurrency("EUR", v)
+	t, _ := trader.New(trader.Currencies{c1, c2}, "usd")
+	return t
+}
+
+func getLots() []Lot {
+	t := getTrader("0.8")
+	a1, _ := t.NewAmountFromString("100", "eur")
+	a2, _ := t.NewAmountFromString("100", "eur")
+	a3, _ := t.NewAmountFromString("50", "usd")
+	return []Lot{
+		{ID: "1", Amount: a1, Rate: decimal.NewFromFloat(1.25)},
+		{ID: "2", Amount: a2, Rate: decimal.NewFromFloat(1.5)},
+		{ID: "3", Amount: a3, Rate: decimal.NewFromFloat(1)},
+	}
+}
+
+func TestUnrealized(t *testing.T) {
+	closing := getTrader("0.5")
+
+	rs, total, err := Unrealized(getLots(), &closing, "usd")
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if len(rs) != 3 {
+		t.Error("Every lot should have been revalued")
+	}
+	if rs[0].Rate.StringFixed(2) != "2.00" {
+		t.Error("The closing rate was incorrect: " + rs[0].Rate.String())
+	}
+	if rs[0].Gain.String(2) != "75.00" || rs[1].Gain.String(2) != "50.00" ||
+		rs[2].Gain.String(2) != "0.00" {
+
+		t.Error("The unrealized gains were incorrectly computed")
+	}
+	if total.String(2) != "125.00" || !total.Currency.Is("usd") {
+		t.Error("The total was incorrectly computed: " + total.String(2))
+	}
+
+	if _, _, err := Unrealized(getLots(), &closing, "gel"); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	// The booked value of the last lot has more decimal places than the
+	// backend of the closing Trader supports
+	a, _ := closing.NewAmountFromString("0.5", "eur")
+	lots := append(getLots(), Lot{ID: "4", Amount: a, Rate: decimal.NewFromFloat(1.25)})
+	fixed := closing.WithBackend(arith.Fixed{Places: 2})
+	if _, _, err := Unrealized(lots, &fixed, "usd"); err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestSettle_FIFO(t *testing.T) {
+	tr := getTrader("0.5")
+	a, _ := tr.NewAmountFromString("150", "eur")
+
+	s, err := Settle(getLots(), a, "usd", FIFO)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if s.Cost.String(2) != "200.00" {
+		t.Error("The cost was incorrectly computed: " + s.Cost.String(2))
+	}
+	if s.Proceeds.String(2) != "300.00" || s.Gain.String(2) != "100.00" {
+		t.Error("The realized gain was incorrectly computed: " + s.Gain.String(2))
+	}
+	if len(s.Remaining) != 2 || s.Remaining[0].ID != "2" ||
+		s.Remaining[0].Amount.String(2) != "50.00" {
+
+		t.Error("The remaining lots were incorrectly computed")
+	}
+
+	a, _ = tr.NewAmountFromString("250", "eur")
+	if _, err := Settle(getLots(), a, "usd", FIFO); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	a, _ = tr.NewAmountFromString("-10", "eur")
+	if _, err := Settle(getLots(), a, "usd", FIFO); err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestSettle_AverageCost(t *testing.T) {
+	tr := getTrader("0.5")
+	a, _ := tr.NewAmountFromString("150", "eur")
+
+	s, err := Settle(getLots(), a, "usd", AverageCost)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if s.Cost.String(2) != "206.25" {
+		t.Error("The cost was incorrectly computed: " + s.Cost.String(2))
+	}
+	if s.Gain.String(2) != "93.75" {
+		t.Error("The realized gain was incorrectly computed: " + s.Gain.String(2))
+	}
+	if len(s.Remaining) != 2 || s.Remaining[1].Amount.String(2) != "50.00" ||
+		s.Remaining[1].Rate.StringFixed(3) != "1.375" {
+
+		t.Error("The remaining lots were incorrectly computed")
+	}
+
+	a, _ = tr.NewAmountFromString("250", "eur")
+	if _, err := Settle(getLots(), a, "usd", AverageCost); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	a, _ = tr.NewAmountFromString("-10", "eur")
+	if _, err := Settle(getLots(), a, "usd", AverageCost); err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestSettle_AverageCostFull(t *testing.T) {
+	tr := getTrader("0.8")
+	a1, _ := tr.NewAmountFromString("100", "eur")
+	a2, _ := tr.NewAmountFromString("200", "eur")
+	lots := []Lot{
+		{ID: "1", Amount: a1, Rate: decimal.NewFromFloat(1.1)},
+		{ID: "2", Amount: a2, Rate: decimal.NewFromFloat(1.2)},
+	}
+
+	a, _ := tr.NewAmountFromString("300", "eur")
+	s, err := Settle(lots, a, "usd", AverageCost)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !s.Cost.Value.Equals(decimal.New(350, 0)) {
+		t.Error("The cost was incorrectly computed: " + s.Cost.Value.String())
+	}
+	if len(s.Remaining) != 0 {
+		t.Error("No lot should have remained")
+	}
+
+	a, _ = tr.NewAmountFromString("150", "eur")
+	s, err = Settle(lots, a, "usd", AverageCost)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !s.Cost.Value.Equals(decimal.New(175, 0)) {
+		t.Error("The cost was incorrectly computed: " + s.Cost.Value.String())
+	}
+}
+
+func TestSettle_Empty(t *testing.T) {
+	tr := getTrader("0.5")
+	zero, _ := tr.NewAmountFromString("0", "eur")
+	lots := []Lot{{ID: "1", Amount: zero, Rate: decimal.NewFromFloat(1.25)}}
+
+	if _, err := Settle(lots, zero, "usd", AverageCost); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	a, _ := tr.NewAmountFromString("10", "eur")
+	if _, err := Settle(lots, a, "usd", AverageCost); err == nil {
+		t.Error("There should have been an error")
+	}
+}
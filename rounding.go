@@ -0,0 +1,128 @@
+package trader
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/processout/decimal"
+)
+
+// RoundingMode is the way values are rounded to a number of decimal places
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest value, and halves away from zero
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest value, and halves to the even
+	// neighbour (banker's rounding)
+	RoundHalfEven
+	// RoundDown rounds toward zero
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundFloor rounds toward negative infinity
+	RoundFloor
+	// RoundCeil rounds toward positive infinity
+	RoundCeil
+)
+
+// roundingModes contains the names of the rounding modes
+var roundingModes = map[RoundingMode]string{
+	RoundHalfUp:   "half-up",
+	RoundHalfEven: "half-even",
+	RoundDown:     "down",
+	RoundUp:       "up",
+	RoundFloor:    "floor",
+	RoundCeil:     "ceil",
+}
+
+// ParseRoundingMode returns the RoundingMode with the given name, as
+// returned by RoundingMode.String
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	for m, name := range roundingModes {
+		if strings.EqualFold(s, name) {
+			return m, nil
+		}
+	}
+
+	return RoundHalfUp, fmt.Errorf("The rounding mode %s is not supported.", s)
+}
+
+// String to implement Stringer interface
+func (m RoundingMode) String() string {
+	if name, ok := roundingModes[m]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("RoundingMode(%d)", int(m))
+}
+
+// Round rounds d to the given number of decimal places
+func (m RoundingMode) Round(d decimal.Decimal, places int32) decimal.Decimal {
+	zero := decimal.New(0, 0)
+	unit := decimal.New(1, -places)
+	t := truncate(d, places)
+	if t.Cmp(d) == 0 {
+		return t
+	}
+
+	// away is the neighbour of t the furthest from zero
+	away := t.Add(unit)
+	if d.Cmp(zero) < 0 {
+		away = t.Sub(unit)
+	}
+
+	switch m {
+	case RoundHalfEven:
+		half := decimal.New(5, -places-1)
+		diff := d.Sub(t)
+		if diff.Cmp(zero) < 0 {
+			diff = zero.Sub(diff)
+		}
+
+		switch diff.Cmp(half) {
+		case -1:
+			return t
+		case 1:
+			return away
+		}
+		if t.Mul(decimal.New(1, places)).IntPart()%2 == 0 {
+			return t
+		}
+		return away
+	case RoundDown:
+		return t
+	case RoundUp:
+		return away
+	case RoundFloor:
+		if d.Cmp(zero) < 0 {
+			return away
+		}
+		return t
+	case RoundCeil:
+		if d.Cmp(zero) > 0 {
+			return away
+		}
+		return t
+	}
+
+	return d.Round(places)
+}
+
+// truncate returns d truncated to the given number of decimal places
+func truncate(d decimal.Decimal, places int32) decimal.Decimal {
+	r := d.Mul(decimal.New(1, places)).Rat()
+	t, _ := decimal.NewFromString(new(big.Int).Quo(r.Num(), r.Denom()).String())
+	return t.Mul(decimal.New(1, -places))
+}
+
+// Round returns a new Amount whose value is the value of a rounded to the
+// decimal places of its currency, using the given RoundingMode. The value
+// of a currency without decimal places, e.g. XAU, is left untouched
+func (a Amount) Round(mode RoundingMode) Amount {
+	if places := a.Currency.DecimalPlaces(); places >= 0 {
+		a.Value = mode.Round(a.Value, int32(places))
+	}
+	return a
+}
@@ -0,0 +1,75 @@
+package trader
+
+import (
+	"testing"
+
+	"github.com/processout/decimal"
+)
+
+func TestParseRoundingMode(t *testing.T) {
+	if _, err := ParseRoundingMode("lel"); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	m, err := ParseRoundingMode("Half-Even")
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if m != RoundHalfEven || m.String() != "half-even" {
+		t.Error("The wrong rounding mode was returned")
+	}
+}
+
+func TestRoundingMode_Round(t *testing.T) {
+	tests := []struct {
+		mode     RoundingMode
+		value    string
+		expected string
+	}{
+		{RoundHalfUp, "1.005", "1.01"},
+		{RoundHalfUp, "-1.005", "-1.01"},
+		{RoundHalfUp, "1.004", "1.00"},
+		{RoundHalfEven, "1.005", "1.00"},
+		{RoundHalfEven, "1.015", "1.02"},
+		{RoundHalfEven, "-1.015", "-1.02"},
+		{RoundHalfEven, "1.0051", "1.01"},
+		{RoundDown, "1.009", "1.00"},
+		{RoundDown, "-1.009", "-1.00"},
+		{RoundUp, "1.001", "1.01"},
+		{RoundUp, "-1.001", "-1.01"},
+		{RoundFloor, "-1.001", "-1.01"},
+		{RoundFloor, "1.009", "1.00"},
+		{RoundCeil, "1.001", "1.01"},
+		{RoundCeil, "-1.009", "-1.00"},
+		{RoundCeil, "1.5", "1.50"},
+	}
+
+	for _, test := range tests {
+		d, _ := decimal.NewFromString(test.value)
+		r := test.mode.Round(d, 2).StringFixed(2)
+		if r != test.expected {
+			t.Errorf("%s of %s should have been %s, got %s", test.mode,
+				test.value, test.expected, r)
+		}
+	}
+}
+
+func TestAmount_Round(t *testing.T) {
+	trader := getTrader()
+	amount, _ := trader.NewAmountFromString("2.345", "usd")
+
+	r := amount.Round(RoundHalfEven)
+	if r.String(3) != "2.340" {
+		t.Error("The amount was incorrectly rounded: " + r.String(3))
+	}
+	if amount.String(3) != "2.345" {
+		t.Error("The original amount shouldn't have been modified")
+	}
+
+	c, _ := NewCurrency("XAU", decimal.NewFromFloat(0.0005))
+	trader, _ = New(Currencies{c}, "xau")
+	amount, _ = trader.NewAmountFromString("61.728", "xau")
+	if r := amount.Round(RoundHalfEven); r.String(3) != "61.728" {
+		t.Error("The amount shouldn't have been rounded: " + r.String(3))
+	}
+}
@@ -0,0 +1,182 @@
+// Package server exposes the conversions of a Trader over HTTP, so that
+// services not written in Go get the exact same results. The rates can be
+// replaced at any time, for instance by watching a rates file.
+//
+// The following endpoints are served, and all respond with JSON:
+//
+//	GET /convert?amount=12.50&from=USD&to=EUR  the trader.Conversion record
+//	GET /rates                                 the Trader and its rates
+//	GET /currencies/{code}                     the ISO 4217 information
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+// Handler is the http.Handler serving the conversions of a Trader. It is
+// safe for concurrent use
+type Handler struct {
+	mu     sync.RWMutex
+	trader trader.Trader
+	mux    *http.ServeMux
+}
+
+// NewHandler creates a new Handler serving the conversions of the given
+// Trader
+func NewHandler(t trader.Trader) *Handler {
+	h := &Handler{
+		trader: t,
+		mux:    http.NewServeMux(),
+	}
+	h.mux.HandleFunc("/convert", h.convert)
+	h.mux.HandleFunc("/rates", h.rates)
+	h.mux.HandleFunc("/currencies/", h.currency)
+
+	return h
+}
+
+// Trader returns the Trader currently used by the Handler
+func (h *Handler) Trader() trader.Trader {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.trader
+}
+
+// SetTrader replaces the Trader used by the Handler. Requests being served
+// keep using the previous Trader
+func (h *Handler) SetTrader(t trader.Trader) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.trader = t
+}
+
+// ServeHTTP to implement http.Handler interface
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed",
+			"The method "+r.Method+" is not allowed.")
+		return
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+// convert serves the conversion of an amount
+func (h *Handler) convert(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	for _, p := range []string{"amount", "from", "to"} {
+		if q.Get(p) == "" {
+			writeError(w, http.StatusBadRequest, "missing_parameter",
+				"The parameter "+p+" is required.")
+			return
+		}
+	}
+
+	d, err := decimal.NewFromString(q.Get("amount"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_amount", err.Error())
+		return
+	}
+
+	t := h.Trader()
+	a, err := t.NewAmount(d, trader.CurrencyCode(q.Get("from")))
+	if err != nil {
+		writeLibraryError(w, err)
+		return
+	}
+	_, c, err := a.ConvertWithTrace(trader.CurrencyCode(q.Get("to")))
+	if err != nil {
+		writeLibraryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c)
+}
+
+// rates serves the rates of the Trader
+func (h *Handler) rates(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.Trader())
+}
+
+// currencyResponse is the response of the currency endpoint
+type currencyResponse struct {
+	Code      string   `json:"code"`
+	Number    uint     `json:"number"`
+	Places    int      `json:"places"`
+	FullName  string   `json:"full_name"`
+	Countries []string `json:"countries"`
+	// Value is the value of the currency relative to the base currency, if
+	// it is supported by the Trader
+	Value *decimal.Decimal `json:"value,omitempty"`
+}
+
+// currency serves the information of a currency
+func (h *Handler) currency(w http.ResponseWriter, r *http.Request) {
+	code := trader.CurrencyCode(strings.TrimPrefix(r.URL.Path, "/currencies/"))
+	i := code.Information()
+	if i == nil {
+		writeLibraryError(w, trader.UnknownCurrencyError{Code: code})
+		return
+	}
+
+	resp := currencyResponse{
+		Code:      code.String(),
+		Number:    i.Number,
+		Places:    i.Places,
+		FullName:  i.FullName,
+		Countries: i.Countries,
+	}
+	if c, err := h.Trader().Currencies.Find(code); err == nil {
+		resp.Value = &c.Value
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// writeJSON writes v as the JSON body of the response, with the given
+// status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// errorResponse is the body of the error responses
+type errorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// writeError writes an error response
+func writeError(w http.ResponseWriter, status int, typ, message string) {
+	var resp errorResponse
+	resp.Error.Type = typ
+	resp.Error.Message = message
+	writeJSON(w, status, resp)
+}
+
+// writeLibraryError writes the error response matching an error returned by
+// the trader package
+func writeLibraryError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case trader.UnknownCurrencyError:
+		writeError(w, http.StatusNotFound, "unknown_currency", err.Error())
+	case trader.CurrencyNotFoundError:
+		writeError(w, http.StatusUnprocessableEntity, "unsupported_currency",
+			err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "internal_error",
+			err.Error())
+	}
+}
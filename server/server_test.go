@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+func getTrader(eur string) trader.Trader {
+	v, _ := decimal.NewFromString(eur)
+	c1, _ := trader.NewCurrency("USD", decimal.NewFromFloat(1))
+	c2, _ := trader.NewCurrency("EUR", v)
+	t, _ := trader.New(trader.Currencies{c1, c2}, "usd")
+	return t
+}
+
+func get(h http.Handler, url string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+
+	var body map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	return w, body
+}
+
+func errorType(body map[string]interface{}) string {
+	e, _ := body["error"].(map[string]interface{})
+	t, _ := e["type"].(string)
+	return t
+}
+
+func TestHandler_Convert(t *testing.T) {
+	h := NewHandler(getTrader("0.8"))
+
+	w, body := get(h, "/convert?amount=12.50&from=usd&to=eur")
+	if w.Code != http.StatusOK {
+		t.Errorf("The status should have been 200, got %d", w.Code)
+	}
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Error("The response should have been JSON")
+	}
+	target, _ := body["target"].(map[string]interface{})
+	if target["value"] != "10" {
+		t.Errorf("The amount was incorrectly converted: %v", target["value"])
+	}
+
+	tests := []struct {
+		url    string
+		status int
+		typ    string
+	}{
+		{"/convert?amount=12.50&from=usd", http.StatusBadRequest, "missing_parameter"},
+		{"/convert?amount=lel&from=usd&to=eur", http.StatusBadRequest, "invalid_amount"},
+		{"/convert?amount=1&from=lel&to=eur", http.StatusUnprocessableEntity, "unsupported_currency"},
+		{"/convert?amount=1&from=usd&to=gbp", http.StatusUnprocessableEntity, "unsupported_currency"},
+	}
+	for _, test := range tests {
+		w, body := get(h, test.url)
+		if w.Code != test.status || errorType(body) != test.typ {
+			t.Errorf("%s should have been a %d %s error, got %d %s", test.url,
+				test.status, test.typ, w.Code, errorType(body))
+		}
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/convert", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("The status should have been 405, got %d", w.Code)
+	}
+}
+
+func TestHandler_Rates(t *testing.T) {
+	h := NewHandler(getTrader("0.8"))
+
+	w, body := get(h, "/rates")
+	if w.Code != http.StatusOK {
+		t.Errorf("The status should have been 200, got %d", w.Code)
+	}
+	if cs, _ := body["currencies"].([]interface{}); len(cs) != 2 {
+		t.Error("The rates were incorrectly served")
+	}
+
+	h.SetTrader(getTrader("0.9"))
+	_, body = get(h, "/convert?amount=10&from=usd&to=eur")
+	target, _ := body["target"].(map[string]interface{})
+	if target["value"] != "9" {
+		t.Errorf("The new trader should have been used: %v", target["value"])
+	}
+}
+
+func TestHandler_Currency(t *testing.T) {
+	h := NewHandler(getTrader("0.8"))
+
+	w, body := get(h, "/currencies/eur")
+	if w.Code != http.StatusOK {
+		t.Errorf("The status should have been 200, got %d", w.Code)
+	}
+	if body["code"] != "EUR" || body["number"] != float64(978) || body["value"] != "0.8" {
+		t.Errorf("The currency was incorrectly served: %v", body)
+	}
+
+	_, body = get(h, "/currencies/xof")
+	if body["full_name"] != "CFA franc BCEAO" || body["value"] != nil {
+		t.Errorf("The currency was incorrectly served: %v", body)
+	}
+
+	w, body = get(h, "/currencies/lel")
+	if w.Code != http.StatusNotFound || errorType(body) != "unknown_currency" {
+		t.Errorf("The status should have been 404, got %d", w.Code)
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"gopkg.in/processout/trader.v2"
+)
+
+// Loader loads a Trader, typically from a file
+type Loader func(path string) (trader.Trader, error)
+
+// WatchFile checks the file at the given path every interval, and replaces
+// the Trader of the Handler with the one loaded from the file whenever the
+// file changes, starting with the first check. It blocks until the context
+// is done. The errors happening while loading the file are passed to
+// onError, if not nil, and the current Trader is kept until the file
+// changes again
+func (h *Handler) WatchFile(ctx context.Context, path string, load Loader,
+	interval time.Duration, onError func(error)) {
+
+	var last os.FileInfo
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			if onError != nil {
+				onError(err)
+			}
+			continue
+		}
+		if last != nil && fi.ModTime().Equal(last.ModTime()) &&
+			fi.Size() == last.Size() {
+
+			continue
+		}
+		last = fi
+
+		t, err := load(path)
+		if err != nil {
+			if onError != nil {
+				onError(err)
+			}
+			continue
+		}
+		h.SetTrader(t)
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gopkg.in/processout/trader.v2"
+)
+
+// write writes the file at the given path, and sets its modification time
+func write(t *testing.T, path, content string, mtime time.Time) {
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandler_WatchFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rates")
+	if err := os.WriteFile(path, []byte("0.8"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loads := make(chan string, 10)
+	load := func(path string) (trader.Trader, error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return trader.Trader{}, err
+		}
+		loads <- string(b)
+		if string(b) == "bad" {
+			return trader.Trader{}, errors.New("bad rates")
+		}
+		return getTrader(string(b)), nil
+	}
+	errs := make(chan error, 10)
+
+	h := NewHandler(getTrader("0.8"))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.WatchFile(ctx, path, load, time.Millisecond, func(err error) {
+			errs <- err
+		})
+		close(done)
+	}()
+
+	write(t, path, "bad", time.Now().Add(time.Hour))
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("The load error should have been reported")
+	}
+	if c, _ := h.Trader().Currencies.Find("eur"); c.Value.String() != "0.8" {
+		t.Error("The previous trader should have been kept")
+	}
+
+	write(t, path, "0.95", time.Now().Add(2*time.Hour))
+	for v := ""; v != "0.95"; {
+		select {
+		case v = <-loads:
+		case <-time.After(5 * time.Second):
+			t.Fatal("The file should have been reloaded")
+		}
+	}
+
+	cancel()
+	<-done
+	if c, _ := h.Trader().Currencies.Find("eur"); c.Value.String() != "0.95" {
+		t.Error("The trader should have been replaced")
+	}
+}
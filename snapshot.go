@@ -0,0 +1,148 @@
+package trader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// SnapshotVersion is the version of the snapshot format written by
+// WriteSnapshot
+const SnapshotVersion = 1
+
+// Snapshot is a set of rates along with their metadata, that can be passed
+// between services. Its content is protected by a SHA-256 hash, so that
+// tampered or truncated snapshots are refused
+type Snapshot struct {
+	// Version is the version of the snapshot format
+	Version int `json:"version"`
+	// BaseCurrency is the code of the base currency of the rates
+	BaseCurrency CurrencyCode `json:"base_currency"`
+	// Rates are the currencies and their value relative to the base
+	// currency, sorted by code
+	Rates Currencies `json:"rates"`
+	// Source identifies where the rates come from
+	Source string `json:"source"`
+	// FetchedAt is the time at which the rates were fetched
+	FetchedAt time.Time `json:"fetched_at"`
+	// ValidUntil is the time until which the rates may be used
+	ValidUntil time.Time `json:"valid_until"`
+	// SHA256 is the hex encoded SHA-256 hash of the content of the snapshot
+	SHA256 string `json:"sha256"`
+}
+
+// NewSnapshot creates a new Snapshot of the rates of the given Trader. Its
+// rates are sorted by code, and its hash is computed
+func NewSnapshot(t Trader, source string, fetchedAt, validUntil time.Time) Snapshot {
+	rates := append(Currencies(nil), t.Currencies...)
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].Code < rates[j].Code
+	})
+
+	s := Snapshot{
+		Version:      SnapshotVersion,
+		BaseCurrency: t.BaseCurrency.Code,
+		Rates:        rates,
+		Source:       source,
+		FetchedAt:    fetchedAt.UTC(),
+		ValidUntil:   validUntil.UTC(),
+	}
+	s.SHA256 = s.Hash()
+	return s
+}
+
+// Hash computes the hex encoded SHA-256 hash of the content of the
+// Snapshot. The content is serialized in a canonical form first, so that the
+// hash doesn't depend on the JSON encoding or on the order of the rates
+func (s Snapshot) Hash() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "version:%d\n", s.Version)
+	fmt.Fprintf(&b, "base_currency:%s\n", s.BaseCurrency)
+	fmt.Fprintf(&b, "source:%q\n", s.Source)
+	fmt.Fprintf(&b, "fetched_at:%s\n", s.FetchedAt.UTC().Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, "valid_until:%s\n", s.ValidUntil.UTC().Format(time.RFC3339Nano))
+
+	rates := make([]string, 0, len(s.Rates))
+	for _, c := range s.Rates {
+		rates = append(rates, fmt.Sprintf("rate:%s=%s\n", c.Code, c.Value))
+	}
+	sort.Strings(rates)
+	for _, r := range rates {
+		b.WriteString(r)
+	}
+
+	sum := sha256.Sum256(b.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// Trader creates a new Trader from the rates of the Snapshot. The currencies
+// of the Trader are sorted by code, and its snapshot ID and time are set to
+// the hash of the Snapshot and the time the rates were fetched
+func (s Snapshot) Trader() (Trader, error) {
+	rates := make(Currencies, 0, len(s.Rates))
+	for _, c := range s.Rates {
+		n, err := NewCurrency(c.Code, c.Value)
+		if err != nil {
+			return emptyTrader, err
+		}
+		rates = append(rates, n)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].Code < rates[j].Code
+	})
+
+	t, err := New(rates, s.BaseCurrency)
+	if err != nil {
+		return emptyTrader, err
+	}
+	t.SnapshotID = s.SHA256
+	if !s.FetchedAt.IsZero() {
+		fetchedAt := s.FetchedAt
+		t.SnapshotTime = &fetchedAt
+	}
+	return t, nil
+}
+
+// WriteSnapshot writes the Snapshot to w as JSON. Its version is set to
+// SnapshotVersion, and its hash is computed before being written
+func WriteSnapshot(w io.Writer, s Snapshot) error {
+	s.Version = SnapshotVersion
+	s.SHA256 = s.Hash()
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(s)
+}
+
+// ReadSnapshot reads a Snapshot written by WriteSnapshot from r. An error
+// is returned if the snapshot is truncated, if its version is not
+// supported, if its content doesn't match its hash, or if its rates can't
+// be used to build a Trader
+func ReadSnapshot(r io.Reader) (Snapshot, error) {
+	var s Snapshot
+	d := json.NewDecoder(r)
+	if err := d.Decode(&s); err != nil {
+		return Snapshot{}, err
+	}
+	if d.More() {
+		return Snapshot{}, errors.New("The snapshot is followed by unexpected data.")
+	}
+
+	if s.Version != SnapshotVersion {
+		return Snapshot{}, fmt.Errorf("The snapshot version %d is not supported.", s.Version)
+	}
+	if s.SHA256 == "" || s.Hash() != s.SHA256 {
+		return Snapshot{}, errors.New("The snapshot content doesn't match its hash.")
+	}
+	if _, err := s.Trader(); err != nil {
+		return Snapshot{}, err
+	}
+
+	return s, nil
+}
@@ -0,0 +1,103 @@
+package trader
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/processout/decimal"
+)
+
+func getSnapshot() Snapshot {
+	c1, _ := NewCurrency("USD", decimal.NewFromFloat(1))
+	c2, _ := NewCurrency("GBP", decimal.NewFromFloat(0.5))
+	c3, _ := NewCurrency("EUR", decimal.NewFromFloat(0.8))
+	trader, _ := New(Currencies{c1, c2, c3}, "usd")
+
+	fetched := time.Date(2016, 9, 23, 15, 0, 0, 0, time.UTC)
+	return NewSnapshot(trader, "test", fetched, fetched.Add(time.Hour))
+}
+
+func TestNewSnapshot(t *testing.T) {
+	s := getSnapshot()
+
+	if s.Version != SnapshotVersion || s.BaseCurrency != "USD" {
+		t.Error("The snapshot was incorrectly created")
+	}
+	if s.Rates[0].Code != "EUR" || s.Rates[1].Code != "GBP" || s.Rates[2].Code != "USD" {
+		t.Error("The rates should have been sorted by code")
+	}
+	if len(s.SHA256) != 64 || s.SHA256 != s.Hash() {
+		t.Error("The hash should have been computed")
+	}
+
+	s2 := s
+	s2.Rates = Currencies{s.Rates[2], s.Rates[0], s.Rates[1]}
+	if s2.Hash() != s.Hash() {
+		t.Error("The hash shouldn't depend on the order of the rates")
+	}
+	s2.Source = "other"
+	if s2.Hash() == s.Hash() {
+		t.Error("The hash should depend on the source")
+	}
+}
+
+func TestReadSnapshot(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteSnapshot(&b, getSnapshot()); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	data := b.String()
+
+	s, err := ReadSnapshot(strings.NewReader(data))
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if s.SHA256 != getSnapshot().SHA256 || len(s.Rates) != 3 {
+		t.Error("The snapshot was incorrectly read")
+	}
+
+	_, err = ReadSnapshot(strings.NewReader(data[:len(data)/2]))
+	if err == nil {
+		t.Error("The truncated snapshot should have been refused")
+	}
+
+	_, err = ReadSnapshot(strings.NewReader(strings.Replace(data, `"0.8"`, `"0.9"`, 1)))
+	if err == nil {
+		t.Error("The tampered snapshot should have been refused")
+	}
+
+	_, err = ReadSnapshot(strings.NewReader(data + "{}"))
+	if err == nil {
+		t.Error("The snapshot followed by data should have been refused")
+	}
+
+	_, err = ReadSnapshot(strings.NewReader(strings.Replace(data, `"version": 1`, `"version": 2`, 1)))
+	if err == nil {
+		t.Error("The unsupported version should have been refused")
+	}
+}
+
+func TestSnapshot_Trader(t *testing.T) {
+	s := getSnapshot()
+
+	trader, err := s.Trader()
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !trader.BaseCurrency.Is("usd") || len(trader.Currencies) != 3 ||
+		trader.Currencies[0].Code != "EUR" {
+
+		t.Error("The trader was incorrectly created")
+	}
+	if trader.SnapshotID != s.SHA256 || trader.SnapshotTime == nil ||
+		!trader.SnapshotTime.Equal(s.FetchedAt) {
+		t.Error("The snapshot of the trader was incorrectly set")
+	}
+
+	s.BaseCurrency = "JPY"
+	if _, err := s.Trader(); err == nil {
+		t.Error("There should have been an error")
+	}
+}
@@ -1,11 +1,59 @@
 // Package trader takes charge of the amounts handling and currency conversions.
 package trader
 
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2/arith"
+)
+
 // Trader is the structure containing the conversions values used to
 // handle the amount conversions
 type Trader struct {
 	Currencies   Currencies `json:"currencies"`
 	BaseCurrency Currency   `json:"base_currency"`
+
+	// SnapshotID identifies the set of rates used by the Trader, if known
+	SnapshotID string `json:"snapshot_id,omitempty"`
+	// SnapshotTime is the time at which the rates were fetched, if known
+	SnapshotTime *time.Time `json:"snapshot_time,omitempty"`
+
+	// ratePrecision is the precision of the rates, see WithRatePrecision
+	ratePrecision precision
+	// resultScale is the scale of the conversions, see WithResultScale
+	resultScale precision
+	// backend does the arithmetic on amounts, see WithBackend
+	backend arith.Backend
+	// frozen is the immutable copy of the Trader referenced by its amounts
+	frozen *frozenTrader
+}
+
+// frozenTrader holds the immutable copy of a Trader, made once and shared
+// by all the copies of the Trader
+type frozenTrader struct {
+	once   sync.Once
+	trader *Trader
+}
+
+// precision is a number of decimal places values are rounded to, if enabled
+type precision struct {
+	enabled bool
+	places  int32
+	mode    RoundingMode
+}
+
+// round rounds d if the precision is enabled, or returns it untouched
+func (p precision) round(d decimal.Decimal) decimal.Decimal {
+	if !p.enabled {
+		return d
+	}
+
+	return p.mode.Round(d, p.places)
 }
 
 var emptyTrader = Trader{}
@@ -23,12 +71,14 @@ func New(currencies Currencies, code CurrencyCode) (Trader, error) {
 	return Trader{
 		Currencies:   currencies,
 		BaseCurrency: c,
+		frozen:       &frozenTrader{},
 	}, nil
 }
 
 // SetBaseCurrency sets the base currency for the Trader, from the given
 // Currency code. If the currency code was not found in the currencies of the
-// Trader, an error is returned
+// Trader, an error is returned. The values of the currencies are left
+// untouched, see Rebase to renormalise them relative to the new base
 func (t *Trader) SetBaseCurrency(code CurrencyCode) error {
 	c, err := t.Currencies.Find(code)
 	if err != nil {
@@ -36,9 +86,215 @@ func (t *Trader) SetBaseCurrency(code CurrencyCode) error {
 	}
 
 	t.BaseCurrency = c
+	t.frozen = &frozenTrader{}
 	return nil
 }
 
+// WithRatePrecision returns a copy of the Trader whose rates, as returned by
+// Amount.RateTo, are computed exactly and rounded half away from zero to
+// the given number of decimal places. By default, rates are computed with
+// the division precision of the decimal package
+func (t Trader) WithRatePrecision(places int32) Trader {
+	t.ratePrecision = precision{
+		enabled: true,
+		places:  places,
+		mode:    RoundHalfUp,
+	}
+	t.frozen = &frozenTrader{}
+	return t
+}
+
+// WithResultScale returns a copy of the Trader whose conversions, made by
+// Amount.ToCurrency, are rounded to the given number of decimal places using
+// the given RoundingMode. By default, converted values are not rounded
+func (t Trader) WithResultScale(places int32, mode RoundingMode) Trader {
+	t.resultScale = precision{
+		enabled: true,
+		places:  places,
+		mode:    mode,
+	}
+	t.frozen = &frozenTrader{}
+	return t
+}
+
+// WithBackend returns a copy of the Trader whose amounts are added,
+// substracted, compared and converted, and whose rates are computed, using
+// the given arith.Backend. By default, the decimal package is used
+// directly. The backend may refuse values, e.g. if they are too large, in
+// which case the operations on the amounts return its error
+func (t Trader) WithBackend(b arith.Backend) Trader {
+	t.backend = b
+	t.frozen = &frozenTrader{}
+	return t
+}
+
+// arithmetic returns the arith.Backend of the Trader
+func (t Trader) arithmetic() arith.Backend {
+	if t.backend == nil {
+		return arith.Decimal{}
+	}
+
+	return t.backend
+}
+
+// frozenTradersSize is the number of immutable copies kept by frozenTraders
+const frozenTradersSize = 16
+
+// frozenTraders keeps the immutable copies of the last traders frozen
+// without the copy made by New, e.g. struct literals, unmarshaled traders
+// or traders modified in place, indexed by their content
+var frozenTraders = struct {
+	sync.Mutex
+	keys    []string
+	traders map[string]*Trader
+}{traders: map[string]*Trader{}}
+
+// freeze returns the immutable copy of the Trader referenced by the amounts
+// it creates. The copy is made once for all the copies of a Trader returned
+// by New or by its With methods. Other traders share the copy of the
+// traders with the same content, which is made again once they are out of
+// the frozenTraders cache
+func (t *Trader) freeze() *Trader {
+	if f := t.frozen; f != nil {
+		f.once.Do(func() {
+			f.trader = t.copy()
+		})
+		if f.trader == t || f.trader.sameFields(*t) {
+			return f.trader
+		}
+	}
+	if t.backend != nil {
+		// The backend can't be part of the key, so the copy isn't shared
+		return t.copy()
+	}
+
+	key := t.key()
+	frozenTraders.Lock()
+	defer frozenTraders.Unlock()
+	if n, ok := frozenTraders.traders[key]; ok {
+		return n
+	}
+
+	if len(frozenTraders.keys) == frozenTradersSize {
+		delete(frozenTraders.traders, frozenTraders.keys[0])
+		frozenTraders.keys = frozenTraders.keys[1:]
+	}
+	n := t.copy()
+	frozenTraders.keys = append(frozenTraders.keys, key)
+	frozenTraders.traders[key] = n
+	return n
+}
+
+// key returns a string identifying the content of the Trader, except its
+// backend
+func (t Trader) key() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v %v %q ", t.ratePrecision, t.resultScale, t.SnapshotID)
+	if t.SnapshotTime != nil {
+		b.WriteString(t.SnapshotTime.Format(time.RFC3339Nano))
+	}
+	for _, c := range append(Currencies{t.BaseCurrency}, t.Currencies...) {
+		fmt.Fprintf(&b, " %s=%se%d", c.Code, c.Value, c.Value.Exponent())
+	}
+
+	return b.String()
+}
+
+// copy returns a copy of the Trader that doesn't share its currencies, and
+// whose own frozen copy is itself
+func (t Trader) copy() *Trader {
+	n := t
+	n.Currencies = append(Currencies(nil), t.Currencies...)
+	n.frozen = &frozenTrader{trader: &n}
+	n.frozen.once.Do(func() {})
+	return &n
+}
+
+// sameTime returns true if t and o are both unknown, or are the same instant
+func sameTime(t, o *time.Time) bool {
+	if t == nil || o == nil {
+		return t == o
+	}
+
+	return t.Equal(*o)
+}
+
+// sameFields returns true if the exported fields of t and o are identical
+func (t Trader) sameFields(o Trader) bool {
+	if t.BaseCurrency != o.BaseCurrency || t.SnapshotID != o.SnapshotID ||
+		!sameTime(t.SnapshotTime, o.SnapshotTime) || len(t.Currencies) != len(o.Currencies) {
+
+		return false
+	}
+
+	for i, c := range t.Currencies {
+		if c != o.Currencies[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rate computes the rate from a currency valued from to a currency valued
+// to with the backend of the Trader, rounded to its rate precision or to
+// the division precision of the decimal package
+func (t Trader) rate(from, to decimal.Decimal) (decimal.Decimal, error) {
+	places := int32(decimal.DivisionPrecision)
+	if t.ratePrecision.enabled {
+		places = t.ratePrecision.places
+	}
+
+	return t.arithmetic().Div(to, from, places)
+}
+
+// Rebase returns a new Trader whose base currency is the currency of the
+// given code, and whose currency values are renormalised so that the new
+// base currency has a value of exactly 1. The new values are rounded to the
+// given number of decimal places, so cross rates are preserved up to that
+// precision. The snapshot metadata and the options of the Trader, such as
+// its rate precision, are kept. An error is returned if the currency code
+// was not found in the currencies of the Trader, or if its value is zero
+func (t Trader) Rebase(code CurrencyCode, precision int32) (Trader, error) {
+	base, err := t.Currencies.Find(code)
+	if err != nil {
+		return emptyTrader, err
+	}
+	if base.Value.Cmp(decimal.New(0, 0)) == 0 {
+		return emptyTrader, fmt.Errorf("The currency %s has a value of zero.", base.Code)
+	}
+
+	currencies := make(Currencies, 0, len(t.Currencies))
+	for _, c := range t.Currencies {
+		if c.Is(base.Code) {
+			c.Value = decimal.New(1, 0)
+		} else {
+			c.Value = div(c.Value, base.Value, precision)
+		}
+		currencies = append(currencies, c)
+	}
+
+	n := t
+	n.Currencies = currencies
+	n.frozen = &frozenTrader{}
+	if n.BaseCurrency, err = currencies.Find(base.Code); err != nil {
+		return emptyTrader, err
+	}
+	return n, nil
+}
+
+// div divides x by y exactly, and rounds the result half away from zero to
+// the given number of decimal places. y must not be zero
+func div(x, y decimal.Decimal, precision int32) decimal.Decimal {
+	if precision < 0 {
+		precision = 0
+	}
+
+	q := new(big.Rat).Quo(x.Rat(), y.Rat())
+	d, _ := decimal.NewFromString(q.FloatString(int(precision)))
+	return d
+}
+
 // Is compares two trader. If the base currency of t and trader are not the same,
 // returns false. If trader does not contain a currency from t, returns false.
 // If one of the currencies of trader does not have the same value as the one
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2/arith"
 )
 
 func TestNew(t *testing.T) {
@@ -116,3 +117,179 @@ func TestTrader_Is(t *testing.T) {
 		t.Error("The traders should have not been equal")
 	}
 }
+
+func TestTrader_Rebase(t *testing.T) {
+	usd, _ := NewCurrency("USD", decimal.NewFromFloat(1))
+	eur, _ := NewCurrency("EUR", decimal.NewFromFloat(0.8))
+	gbp, _ := NewCurrency("GBP", decimal.NewFromFloat(0.7))
+	jpy, _ := NewCurrency("JPY", decimal.NewFromFloat(110))
+	trader, _ := New(Currencies{usd, eur, gbp, jpy}, "usd")
+	trader.SnapshotID = "snapshot-1"
+
+	if _, err := trader.Rebase("gel", 12); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	rebased, err := trader.Rebase("eur", 12)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !rebased.BaseCurrency.Is("eur") || rebased.BaseCurrency.Value.String() != "1" {
+		t.Error("The base currency should have been set with a value of 1")
+	}
+	if rebased.SnapshotID != "snapshot-1" {
+		t.Error("The snapshot ID should have been kept")
+	}
+
+	c, _ := rebased.Currencies.Find("usd")
+	if c.Value.String() != "1.25" {
+		t.Error("The USD value was incorrectly renormalised: " + c.Value.String())
+	}
+	c, _ = rebased.Currencies.Find("gbp")
+	if c.Value.String() != "0.875" {
+		t.Error("The GBP value was incorrectly renormalised: " + c.Value.String())
+	}
+	if c, _ := trader.Currencies.Find("usd"); c.Value.String() != "1" {
+		t.Error("The original trader shouldn't have been modified")
+	}
+
+	// Cross rates must be preserved to the requested precision
+	for _, from := range []CurrencyCode{"USD", "EUR", "GBP", "JPY"} {
+		for _, to := range []CurrencyCode{"USD", "EUR", "GBP", "JPY"} {
+			a, _ := trader.NewAmountFromString("1", from)
+			b, _ := rebased.NewAmountFromString("1", from)
+			r1, _ := a.RateTo(to)
+			r2, _ := b.RateTo(to)
+			if r1.StringFixed(8) != r2.StringFixed(8) {
+				t.Errorf("The rate from %s to %s changed from %s to %s",
+					from, to, r1, r2)
+			}
+		}
+	}
+
+	// The options of the Trader must be kept
+	rounded := trader.WithRatePrecision(2).WithResultScale(0, RoundDown)
+	rebased, _ = rounded.Rebase("eur", 12)
+	a, _ := rebased.NewAmountFromString("10", "eur")
+	if r, _ := a.RateTo("gbp"); r.String() != "0.88" {
+		t.Error("The rate precision should have been kept: " + r.String())
+	}
+	if b, _ := a.ToCurrency("gbp"); b.Value.String() != "8" {
+		t.Error("The result scale should have been kept: " + b.Value.String())
+	}
+
+	zero, _ := NewCurrency("GEL", decimal.New(0, 0))
+	trader.Currencies = append(trader.Currencies, zero)
+	if _, err := trader.Rebase("gel", 12); err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestTrader_WithRatePrecision(t *testing.T) {
+	usd, _ := NewCurrency("USD", decimal.NewFromFloat(1))
+	eur, _ := NewCurrency("EUR", decimal.NewFromFloat(3))
+	trader, _ := New(Currencies{usd, eur}, "usd")
+
+	precise := trader.WithRatePrecision(12)
+	a, _ := precise.NewAmountFromString("1", "eur")
+	rate, _ := a.RateTo("usd")
+	if rate.String() != "0.333333333333" {
+		t.Error("The rate should have been rounded to 12 places: " + rate.String())
+	}
+
+	precise = precise.WithRatePrecision(30)
+	a, _ = precise.NewAmountFromString("1", "eur")
+	rate, _ = a.RateTo("usd")
+	if rate.String() != "0.333333333333333333333333333333" {
+		t.Error("The rate should have been computed to 30 places: " + rate.String())
+	}
+
+	a, _ = trader.NewAmountFromString("1", "eur")
+	rate, _ = a.RateTo("usd")
+	if rate.String() == "0.333333333333" {
+		t.Error("The original trader shouldn't have been modified")
+	}
+}
+
+func TestTrader_WithResultScale(t *testing.T) {
+	trader := getTrader().WithResultScale(2, RoundHalfEven)
+
+	a, _ := trader.NewAmountFromString("1.03125", "usd")
+	n, _ := a.ToCurrency("eur")
+	if n.Value.String() != "0.82" {
+		t.Error("The converted value should have been rounded: " + n.Value.String())
+	}
+
+	n, _ = a.ToCurrency("usd")
+	if n.Value.String() != "1.03125" {
+		t.Error("The value shouldn't have been rounded without conversion")
+	}
+}
+
+func TestTrader_WithBackend(t *testing.T) {
+	backends := []arith.Backend{arith.Decimal{}, arith.Rat{}, arith.Fixed{Places: 4}}
+	for _, b := range backends {
+		trader := getTrader().WithRatePrecision(4).WithBackend(b)
+
+		a, _ := trader.NewAmountFromString("10.5", "usd")
+		c, _ := trader.NewAmountFromString("4", "eur")
+		sum, err := a.Add(c)
+		if err != nil || sum.Value.Cmp(decimal.NewFromFloat(15.5)) != 0 {
+			t.Errorf("%T: the sum was incorrectly computed: %s", b, sum.Value)
+		}
+		n, err := a.ToCurrency("eur")
+		if err != nil || n.Value.Cmp(decimal.NewFromFloat(8.4)) != 0 {
+			t.Errorf("%T: the conversion was incorrectly computed: %s", b, n.Value)
+		}
+		if !a.GreaterThan(c) {
+			t.Errorf("%T: 10.5 USD should have been greater than 4 EUR", b)
+		}
+	}
+
+	trader := getTrader().WithBackend(arith.Fixed{Places: 2})
+	a, _ := trader.NewAmountFromString("10.001", "usd")
+	c, _ := trader.NewAmountFromString("4", "usd")
+	if _, err := a.Add(c); err != arith.ErrPrecision {
+		t.Error("The backend should have refused the value")
+	}
+	if _, err := c.Cmp(a); err != arith.ErrPrecision {
+		t.Error("The backend should have refused the value")
+	}
+
+	// The rates between currencies of very different values fit
+	usd, _ := NewCurrency("USD", decimal.NewFromFloat(1))
+	krw, _ := NewCurrency("KRW", decimal.NewFromFloat(1350))
+	trader, _ = New(Currencies{usd, krw}, "usd")
+	for _, b := range backends {
+		trader := trader.WithBackend(b)
+
+		a, _ := trader.NewAmountFromString("10", "usd")
+		if n, err := a.ToCurrency("krw"); err != nil || n.Value.Cmp(decimal.New(13500, 0)) != 0 {
+			t.Errorf("%T: the conversion was incorrectly computed: %s", b, n.Value)
+		}
+		a, _ = trader.NewAmountFromString("13500", "krw")
+		if n, err := a.ToCurrency("usd"); err != nil || n.Value.Round(2).Cmp(decimal.New(10, 0)) != 0 {
+			t.Errorf("%T: the conversion was incorrectly computed: %s", b, n.Value)
+		}
+	}
+
+	// The rates are computed by the backend too
+	divs := 0
+	trader = getTrader().WithBackend(countingBackend{divs: &divs})
+	a, _ = trader.NewAmountFromString("10", "usd")
+	if _, err := a.RateTo("eur"); err != nil || divs != 1 {
+		t.Error("The rate should have been computed by the backend")
+	}
+}
+
+// countingBackend is the decimal Backend counting its divisions
+type countingBackend struct {
+	arith.Decimal
+	divs *int
+}
+
+// Div returns x / y, and counts the division
+func (b countingBackend) Div(x, y decimal.Decimal, places int32) (decimal.Decimal, error) {
+	*b.divs++
+	return b.Decimal.Div(x, y, places)
+}
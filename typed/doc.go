@@ -0,0 +1,7 @@
+// Package typed provides amounts whose currency is part of their type, so
+// that mixing currencies by accident fails to compile. Adding a Money[USD]
+// to a Money[EUR] is a compilation error: they must be converted
+// explicitly with Convert, through a Trader.
+//
+// The package requires Go 1.18 or later.
+package typed
@@ -0,0 +1,208 @@
+//go:build go1.18
+
+package typed
+
+import (
+	"encoding/json"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+// Currency is implemented by the phantom types identifying the currency of
+// a Money. New currencies can be added by declaring an empty struct type
+// whose Code method returns an ISO 4217 code
+type Currency interface {
+	Code() trader.CurrencyCode
+}
+
+// USD is the phantom type of the US dollar
+type USD struct{}
+
+// Code returns the code of the currency
+func (USD) Code() trader.CurrencyCode { return "USD" }
+
+// EUR is the phantom type of the euro
+type EUR struct{}
+
+// Code returns the code of the currency
+func (EUR) Code() trader.CurrencyCode { return "EUR" }
+
+// GBP is the phantom type of the pound sterling
+type GBP struct{}
+
+// Code returns the code of the currency
+func (GBP) Code() trader.CurrencyCode { return "GBP" }
+
+// JPY is the phantom type of the Japanese yen
+type JPY struct{}
+
+// Code returns the code of the currency
+func (JPY) Code() trader.CurrencyCode { return "JPY" }
+
+// CHF is the phantom type of the Swiss franc
+type CHF struct{}
+
+// Code returns the code of the currency
+func (CHF) Code() trader.CurrencyCode { return "CHF" }
+
+// CAD is the phantom type of the Canadian dollar
+type CAD struct{}
+
+// Code returns the code of the currency
+func (CAD) Code() trader.CurrencyCode { return "CAD" }
+
+// AUD is the phantom type of the Australian dollar
+type AUD struct{}
+
+// Code returns the code of the currency
+func (AUD) Code() trader.CurrencyCode { return "AUD" }
+
+// CNY is the phantom type of the Chinese yuan
+type CNY struct{}
+
+// Code returns the code of the currency
+func (CNY) Code() trader.CurrencyCode { return "CNY" }
+
+// Money is a value in the currency C. The zero value is a zero amount
+type Money[C Currency] struct {
+	value decimal.Decimal
+}
+
+// New creates a new Money in the currency C
+func New[C Currency](d decimal.Decimal) Money[C] {
+	return Money[C]{value: d}
+}
+
+// NewFromString creates a new Money in the currency C from a string.
+// Returns an error if the string could not be parsed
+func NewFromString[C Currency](s string) (Money[C], error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return Money[C]{}, err
+	}
+
+	return New[C](d), nil
+}
+
+// code returns the currency code of C
+func code[C Currency]() trader.CurrencyCode {
+	var c C
+	return c.Code()
+}
+
+// Value returns the value of m
+func (m Money[C]) Value() decimal.Decimal {
+	return m.value
+}
+
+// Code returns the currency code of m
+func (m Money[C]) Code() trader.CurrencyCode {
+	return code[C]()
+}
+
+// Add returns the sum of m and n
+func (m Money[C]) Add(n Money[C]) Money[C] {
+	return New[C](m.value.Add(n.value))
+}
+
+// Sub returns the substraction of n from m
+func (m Money[C]) Sub(n Money[C]) Money[C] {
+	return New[C](m.value.Sub(n.value))
+}
+
+// Mul returns m multiplied by the given factor
+func (m Money[C]) Mul(d decimal.Decimal) Money[C] {
+	return New[C](m.value.Mul(d))
+}
+
+// Neg returns the opposite of m
+func (m Money[C]) Neg() Money[C] {
+	return New[C](decimal.New(0, 0).Sub(m.value))
+}
+
+// Cmp compares m and n, and returns -1, 0 or +1 if m is respectively
+// smaller than, equal to or greater than n
+func (m Money[C]) Cmp(n Money[C]) int {
+	return m.value.Cmp(n.value)
+}
+
+// Round returns m rounded to the decimal places of its currency, using the
+// given RoundingMode
+func (m Money[C]) Round(mode trader.RoundingMode) Money[C] {
+	return New[C](m.Money().Round(mode).Value)
+}
+
+// String returns m in its canonical form, e.g. "USD 12.34"
+func (m Money[C]) String() string {
+	return m.Money().String()
+}
+
+// Money returns m as a dynamic trader.Money
+func (m Money[C]) Money() trader.Money {
+	return trader.Money{
+		Value: m.value,
+		Code:  m.Code(),
+	}
+}
+
+// Amount creates a dynamic trader.Amount from m, using the given Trader.
+// An error is returned if the currency is not supported by the Trader
+func (m Money[C]) Amount(t *trader.Trader) (trader.Amount, error) {
+	return m.Money().Amount(t)
+}
+
+// FromMoney creates a Money in the currency C from a dynamic trader.Money.
+// An error is returned if the Money is not in the currency C
+func FromMoney[C Currency](m trader.Money) (Money[C], error) {
+	if !m.SameCurrency(trader.Money{Code: code[C]()}) {
+		return Money[C]{}, trader.ErrCurrencyMismatch
+	}
+
+	return New[C](m.Value), nil
+}
+
+// FromAmount creates a Money in the currency C from a dynamic
+// trader.Amount. An error is returned if the Amount is not in the currency
+// C: it must be converted first
+func FromAmount[C Currency](a trader.Amount) (Money[C], error) {
+	return FromMoney[C](a.Money())
+}
+
+// Convert converts m from the currency From to the currency To, using the
+// given Trader. An error is returned if one of the currencies is not
+// supported by the Trader
+func Convert[From, To Currency](t *trader.Trader, m Money[From]) (Money[To], error) {
+	a, err := m.Amount(t)
+	if err != nil {
+		return Money[To]{}, err
+	}
+
+	n, err := a.ToCurrency(code[To]())
+	if err != nil {
+		return Money[To]{}, err
+	}
+
+	return FromAmount[To](n)
+}
+
+// MarshalJSON implements json.Marshaler. m is encoded like a trader.Money
+func (m Money[C]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Money())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. An error is returned if the
+// encoded currency is not C
+func (m *Money[C]) UnmarshalJSON(data []byte) error {
+	var d trader.Money
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+
+	n, err := FromMoney[C](d)
+	if err != nil {
+		return err
+	}
+	*m = n
+	return nil
+}
@@ -0,0 +1,118 @@
+//go:build go1.18
+
+package typed
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/processout/decimal"
+	"gopkg.in/processout/trader.v2"
+)
+
+func getTrader() trader.Trader {
+	c1, _ := trader.NewCurrency("USD", decimal.NewFromFloat(1))
+	c2, _ := trader.NewCurrency("EUR", decimal.NewFromFloat(0.8))
+	t, _ := trader.New(trader.Currencies{c1, c2}, "usd")
+	return t
+}
+
+func TestMoney(t *testing.T) {
+	m1, err := NewFromString[USD]("12.3")
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	m2 := New[USD](decimal.NewFromFloat(0.7))
+
+	if s := m1.Add(m2).String(); s != "USD 13.00" {
+		t.Errorf("The sum was incorrectly computed: %s", s)
+	}
+	if s := m1.Sub(m2).Neg().String(); s != "USD -11.60" {
+		t.Errorf("The difference was incorrectly computed: %s", s)
+	}
+	if s := m2.Mul(decimal.NewFromFloat(3)).String(); s != "USD 2.10" {
+		t.Errorf("The product was incorrectly computed: %s", s)
+	}
+	if m1.Cmp(m2) <= 0 {
+		t.Error("Answer should have been positive (12.3 > 0.7)")
+	}
+
+	var zero Money[JPY]
+	if zero.Code() != "JPY" || zero.String() != "JPY 0" {
+		t.Error("The zero value should have been usable")
+	}
+	if s := New[JPY](decimal.NewFromFloat(12.5)).Round(trader.RoundHalfEven).String(); s != "JPY 12" {
+		t.Errorf("The money was incorrectly rounded: %s", s)
+	}
+
+	if _, err := NewFromString[USD]("twelve"); err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tr := getTrader()
+	m := New[USD](decimal.NewFromFloat(10))
+
+	eur, err := Convert[USD, EUR](&tr, m)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if eur.Cmp(New[EUR](decimal.NewFromFloat(8))) != 0 {
+		t.Errorf("The money was incorrectly converted: %s", eur)
+	}
+
+	if _, err := Convert[USD, GBP](&tr, m); err == nil {
+		t.Error("There should have been an error")
+	}
+	if _, err := Convert[GBP, USD](&tr, New[GBP](decimal.New(1, 0))); err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestBridges(t *testing.T) {
+	tr := getTrader()
+	m := New[EUR](decimal.NewFromFloat(8))
+
+	a, err := m.Amount(&tr)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if !a.Currency.Is("eur") || !a.Value.Equals(m.Value()) {
+		t.Error("The amount was incorrectly created")
+	}
+
+	n, err := FromAmount[EUR](a)
+	if err != nil || n.Cmp(m) != 0 {
+		t.Error("The money should have been kept")
+	}
+	if _, err := FromAmount[USD](a); err != trader.ErrCurrencyMismatch {
+		t.Error("There should have been an error")
+	}
+
+	if _, err := FromMoney[EUR](m.Money()); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if _, err := FromMoney[USD](m.Money()); err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	m := New[EUR](decimal.NewFromFloat(8))
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+
+	var n Money[EUR]
+	if err := json.Unmarshal(b, &n); err != nil || n.Cmp(m) != 0 {
+		t.Errorf("The money was incorrectly unmarshaled: %s", b)
+	}
+
+	var u Money[USD]
+	if err := json.Unmarshal(b, &u); err == nil {
+		t.Error("The currency should have been checked")
+	}
+}
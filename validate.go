@@ -0,0 +1,170 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/processout/decimal"
+)
+
+// ValidationErrors lists all the problems found while validating rates
+type ValidationErrors []error
+
+// Error to implement error interface
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, " ")
+}
+
+// Validate verifies that the currencies can be used for conversions: every
+// code must be part of ISO 4217 and appear only once, and every value must
+// be strictly positive. It returns nil if no problem was found, or
+// ValidationErrors listing all of them otherwise
+func (c Currencies) Validate() error {
+	var errs ValidationErrors
+	zero := decimal.New(0, 0)
+	seen := map[CurrencyCode]bool{}
+	for _, v := range c {
+		code := v.Code.format()
+		if !code.Verify() {
+			errs = append(errs, UnknownCurrencyError{code})
+		}
+		if seen[code] {
+			errs = append(errs, fmt.Errorf("The currency code %s is duplicated.", code))
+		}
+		seen[code] = true
+
+		if v.Value.Cmp(zero) <= 0 {
+			errs = append(errs, fmt.Errorf("The value of the currency %s must be positive, got %s.",
+				code, v.Value))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Validate verifies the currencies of the Trader (see Currencies.Validate),
+// and that its base currency is part of them with a value of 1. It returns
+// nil if no problem was found, or ValidationErrors listing all of them
+// otherwise
+func (t Trader) Validate() error {
+	var errs ValidationErrors
+	if err := t.Currencies.Validate(); err != nil {
+		errs = append(errs, err.(ValidationErrors)...)
+	}
+
+	base, err := t.Currencies.Find(t.BaseCurrency.Code)
+	switch {
+	case err != nil:
+		errs = append(errs, err)
+	case base.Value.Cmp(decimal.New(1, 0)) != 0:
+		errs = append(errs, fmt.Errorf("The value of the base currency %s must be 1, got %s.",
+			base.Code, base.Value))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// RateMove is the move of the rate of a currency between two snapshots of
+// rates
+type RateMove struct {
+	Code CurrencyCode `json:"code"`
+	// Old is the rate in the old snapshot, zero if the currency was added
+	Old decimal.Decimal `json:"old"`
+	// New is the rate in the new snapshot, zero if the currency was removed
+	New decimal.Decimal `json:"new"`
+	// Change is the change of the rate, in percent
+	Change decimal.Decimal `json:"change"`
+	// Removed is true if the currency is missing from the new snapshot
+	Removed bool `json:"removed"`
+	// Added is true if the currency is missing from the old snapshot
+	Added bool `json:"added"`
+}
+
+// CompareSnapshots compares the rates of before and after, relative to the
+// base currency of before, and returns the moves of more than threshold
+// percent (e.g. 5 for 5%), sorted by code. Currencies missing from before
+// or after are always returned. An error is returned if the base currency
+// of before is not supported by after, as the rates can't be compared
+func CompareSnapshots(before, after Snapshot, threshold decimal.Decimal) ([]RateMove, error) {
+	return compareRates(before.Rates, before.BaseCurrency, after.Rates, threshold)
+}
+
+// compareRates compares the currencies before and after, relative to the
+// base currency of the given code, like CompareSnapshots
+func compareRates(before Currencies, base CurrencyCode, after Currencies,
+	threshold decimal.Decimal) ([]RateMove, error) {
+
+	oldBase, err := before.Find(base)
+	if err != nil {
+		return nil, err
+	}
+	newBase, err := after.Find(base)
+	if err != nil {
+		return nil, err
+	}
+
+	zero := decimal.New(0, 0)
+	if oldBase.Value.Cmp(zero) == 0 || newBase.Value.Cmp(zero) == 0 {
+		return nil, errors.New("The value of the base currency can't be zero.")
+	}
+
+	var moves []RateMove
+	for _, c := range before {
+		m := RateMove{
+			Code: c.Code,
+			Old:  c.Value.Div(oldBase.Value),
+		}
+
+		n, err := after.Find(c.Code)
+		if err != nil {
+			m.Removed = true
+			moves = append(moves, m)
+			continue
+		}
+
+		m.New = n.Value.Div(newBase.Value)
+		if m.Old.Cmp(zero) == 0 {
+			if m.New.Cmp(zero) != 0 {
+				moves = append(moves, m)
+			}
+			continue
+		}
+
+		m.Change = m.New.Sub(m.Old).Div(m.Old).Mul(decimal.New(100, 0))
+		change := m.Change
+		if change.Cmp(zero) < 0 {
+			change = zero.Sub(change)
+		}
+		if change.Cmp(threshold) > 0 {
+			moves = append(moves, m)
+		}
+	}
+
+	for _, c := range after {
+		if _, err := before.Find(c.Code); err != nil {
+			moves = append(moves, RateMove{
+				Code:  c.Code,
+				New:   c.Value.Div(newBase.Value),
+				Added: true,
+			})
+		}
+	}
+
+	sort.Slice(moves, func(i, j int) bool {
+		return moves[i].Code < moves[j].Code
+	})
+	return moves, nil
+}
@@ -0,0 +1,109 @@
+package trader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/processout/decimal"
+)
+
+func TestCurrencies_Validate(t *testing.T) {
+	c1, _ := NewCurrency("USD", decimal.NewFromFloat(1))
+	c2, _ := NewCurrency("EUR", decimal.NewFromFloat(0.8))
+
+	if err := (Currencies{c1, c2}).Validate(); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+
+	bad := Currencies{
+		c1,
+		c2,
+		{Code: "eur", Value: decimal.NewFromFloat(0.9)},
+		{Code: "GBP", Value: decimal.New(0, 0)},
+		{Code: "JPY", Value: decimal.NewFromFloat(-100)},
+		{Code: "LEL", Value: decimal.NewFromFloat(1)},
+	}
+	err := bad.Validate()
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatal("The error should have been a ValidationErrors")
+	}
+	if len(errs) != 4 {
+		t.Errorf("All the problems should have been returned: %s", err)
+	}
+	if _, ok := errs[3].(UnknownCurrencyError); !ok {
+		t.Error("The unknown currency should have been reported")
+	}
+}
+
+func TestTrader_Validate(t *testing.T) {
+	if err := getTrader().Validate(); err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+
+	// The base currency of getTrader2 has a value of 0.8
+	err := getTrader2().Validate()
+	if errs, ok := err.(ValidationErrors); !ok || len(errs) != 1 {
+		t.Errorf("The base currency value should have been reported: %v", err)
+	}
+
+	trader := getTrader()
+	trader.BaseCurrency.Code = "GBP"
+	trader.Currencies = append(trader.Currencies, Currency{Code: "EUR", Value: decimal.New(0, 0)})
+	err = trader.Validate()
+	if errs, ok := err.(ValidationErrors); !ok || len(errs) != 3 {
+		t.Errorf("All the problems should have been returned: %v", err)
+	}
+}
+
+func TestCompareSnapshots(t *testing.T) {
+	c1, _ := NewCurrency("USD", decimal.NewFromFloat(1))
+	c2, _ := NewCurrency("EUR", decimal.NewFromFloat(0.8))
+	c3, _ := NewCurrency("GBP", decimal.NewFromFloat(0.5))
+	c4, _ := NewCurrency("JPY", decimal.NewFromFloat(100))
+	old, _ := New(Currencies{c1, c2, c3, c4}, "usd")
+
+	n1, _ := NewCurrency("USD", decimal.NewFromFloat(1))
+	n2, _ := NewCurrency("EUR", decimal.NewFromFloat(0.82))
+	n3, _ := NewCurrency("GBP", decimal.NewFromFloat(0.6))
+	n4, _ := NewCurrency("CHF", decimal.NewFromFloat(0.9))
+	cur, _ := New(Currencies{n1, n2, n3, n4}, "usd")
+
+	moves, err := CompareSnapshots(snapshot(old), snapshot(cur), decimal.NewFromFloat(5))
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+	if len(moves) != 3 {
+		t.Fatalf("Three moves should have been returned: %+v", moves)
+	}
+	if moves[0].Code != "CHF" || !moves[0].Added || moves[0].New.StringFixed(1) != "0.9" {
+		t.Errorf("The CHF addition should have been returned: %+v", moves[0])
+	}
+	if moves[1].Code != "GBP" || moves[1].Change.StringFixed(0) != "20" {
+		t.Errorf("The GBP move was incorrectly computed: %+v", moves[1])
+	}
+	if moves[2].Code != "JPY" || !moves[2].Removed {
+		t.Errorf("The JPY removal should have been returned: %+v", moves[2])
+	}
+
+	moves, _ = CompareSnapshots(snapshot(old), snapshot(cur), decimal.NewFromFloat(1))
+	if len(moves) != 4 || moves[1].Code != "EUR" {
+		t.Errorf("Four moves should have been returned: %+v", moves)
+	}
+
+	_, err = CompareSnapshots(snapshot(old), snapshot(getTrader2()), decimal.NewFromFloat(5))
+	if err != nil {
+		t.Error("There shouldn't have been an error")
+	}
+
+	c5, _ := NewCurrency("GEL", decimal.NewFromFloat(1))
+	other, _ := New(Currencies{c5}, "gel")
+	if _, err := CompareSnapshots(snapshot(old), snapshot(other), decimal.NewFromFloat(5)); err == nil {
+		t.Error("There should have been an error")
+	}
+}
+
+// snapshot returns a Snapshot of the rates of the given Trader
+func snapshot(t Trader) Snapshot {
+	return NewSnapshot(t, "test", time.Time{}, time.Time{})
+}
@@ -0,0 +1,545 @@
+package trader
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/processout/decimal"
+)
+
+// Language is a language amounts can be written in words in
+type Language string
+
+// The languages amounts can be written in words in
+const (
+	English Language = "en"
+	// IndianEnglish is English with the Indian grouping of digits, in
+	// lakhs (100,000) and crores (10,000,000)
+	IndianEnglish Language = "en-IN"
+	French        Language = "fr"
+	Spanish       Language = "es"
+)
+
+// maxWords is the bound of the values that can be written in words
+var maxWords = decimal.New(1, 15)
+
+// unitNames are the names of the major and minor units of a currency, in
+// the singular and in the plural
+type unitNames struct {
+	Major  string
+	Majors string
+	Minor  string
+	Minors string
+}
+
+// major returns the name of the major unit for the given count
+func (u unitNames) major(singular bool) string {
+	if singular {
+		return u.Major
+	}
+
+	return u.Majors
+}
+
+// minor returns the name of the minor unit for the given count
+func (u unitNames) minor(singular bool) string {
+	if singular {
+		return u.Minor
+	}
+
+	return u.Minors
+}
+
+// language describes how amounts are written in words in a Language
+type language struct {
+	// number writes a number in words. apocope is set when the number is
+	// followed by a noun, e.g. "un" rather than "uno" in Spanish
+	number func(n uint64, apocope bool) string
+	// singular returns true if the units are singular for the count n
+	singular func(n uint64) bool
+	minus    string
+	and      string
+	// point separates the integer part from the digits of the fraction,
+	// for the currencies without minor unit
+	point string
+	// of is inserted between round millions and the unit, e.g. "un million
+	// de dollars", and is elided before a vowel if elide is set
+	of    string
+	elide bool
+	// names are the names of the units of the currencies in the language.
+	// If fallback is set, the other currencies are named after their full
+	// name, which is in English
+	names    map[CurrencyCode]unitNames
+	fallback bool
+}
+
+// englishNames are the names of the units of the currencies in English
+var englishNames = map[CurrencyCode]unitNames{
+	"ARS": {"peso", "pesos", "centavo", "centavos"},
+	"AUD": {"dollar", "dollars", "cent", "cents"},
+	"BRL": {"real", "reais", "centavo", "centavos"},
+	"CAD": {"dollar", "dollars", "cent", "cents"},
+	"CHF": {"franc", "francs", "centime", "centimes"},
+	"CLP": {"peso", "pesos", "centavo", "centavos"},
+	"CNY": {"yuan", "yuan", "fen", "fen"},
+	"COP": {"peso", "pesos", "centavo", "centavos"},
+	"DKK": {"krone", "kroner", "øre", "øre"},
+	"EUR": {"euro", "euros", "cent", "cents"},
+	"GBP": {"pound", "pounds", "penny", "pence"},
+	"INR": {"rupee", "rupees", "paisa", "paise"},
+	"JPY": {"yen", "yen", "sen", "sen"},
+	"KWD": {"dinar", "dinars", "fils", "fils"},
+	"MXN": {"peso", "pesos", "centavo", "centavos"},
+	"NOK": {"krone", "kroner", "øre", "øre"},
+	"PEN": {"sol", "soles", "céntimo", "céntimos"},
+	"SEK": {"krona", "kronor", "öre", "öre"},
+	"USD": {"dollar", "dollars", "cent", "cents"},
+	"ZAR": {"rand", "rand", "cent", "cents"},
+}
+
+// languages are the supported languages
+var languages = map[Language]language{
+	English: {
+		number:   func(n uint64, _ bool) string { return englishWords(n) },
+		singular: func(n uint64) bool { return n == 1 },
+		minus:    "minus",
+		and:      "and",
+		point:    "point",
+		names:    englishNames,
+		fallback: true,
+	},
+	IndianEnglish: {
+		number:   func(n uint64, _ bool) string { return indianWords(n) },
+		singular: func(n uint64) bool { return n == 1 },
+		minus:    "minus",
+		and:      "and",
+		point:    "point",
+		names:    englishNames,
+		fallback: true,
+	},
+	French: {
+		number:   func(n uint64, _ bool) string { return frenchWords(n) },
+		singular: func(n uint64) bool { return n < 2 },
+		minus:    "moins",
+		and:      "et",
+		point:    "virgule",
+		of:       "de",
+		elide:    true,
+		names: map[CurrencyCode]unitNames{
+			"CAD": {"dollar", "dollars", "cent", "cents"},
+			"CHF": {"franc", "francs", "centime", "centimes"},
+			"EUR": {"euro", "euros", "centime", "centimes"},
+			"GBP": {"livre sterling", "livres sterling", "penny", "pence"},
+			"INR": {"roupie", "roupies", "paisa", "paise"},
+			"JPY": {"yen", "yens", "sen", "sen"},
+			"MXN": {"peso", "pesos", "centavo", "centavos"},
+			"USD": {"dollar", "dollars", "cent", "cents"},
+			"XOF": {"franc CFA", "francs CFA", "centime", "centimes"},
+		},
+	},
+	Spanish: {
+		number:   spanishWords,
+		singular: func(n uint64) bool { return n == 1 },
+		minus:    "menos",
+		and:      "con",
+		point:    "coma",
+		of:       "de",
+		names: map[CurrencyCode]unitNames{
+			"ARS": {"peso", "pesos", "centavo", "centavos"},
+			"BRL": {"real", "reales", "centavo", "centavos"},
+			"CLP": {"peso", "pesos", "centavo", "centavos"},
+			"COP": {"peso", "pesos", "centavo", "centavos"},
+			"EUR": {"euro", "euros", "céntimo", "céntimos"},
+			"JPY": {"yen", "yenes", "sen", "sen"},
+			"MXN": {"peso", "pesos", "centavo", "centavos"},
+			"PEN": {"sol", "soles", "céntimo", "céntimos"},
+			"USD": {"dólar", "dólares", "centavo", "centavos"},
+			"UYU": {"peso", "pesos", "centésimo", "centésimos"},
+		},
+	},
+}
+
+// unitNames returns the names of the units of the given currency in the
+// language. If the language allows it, the currencies without names are
+// named after the full name of the currency, e.g. "Swedish krona/kronor",
+// and have no name for their minor unit. An error is returned otherwise
+func (l language) unitNames(code CurrencyCode, lang Language) (unitNames, error) {
+	if u, ok := l.names[code.format()]; ok {
+		return u, nil
+	}
+
+	info := code.Information()
+	if info == nil {
+		return unitNames{}, UnknownCurrencyError{code}
+	}
+	if !l.fallback {
+		return unitNames{}, fmt.Errorf("The currency %s has no name in %s.", code.format(), lang)
+	}
+
+	var u unitNames
+	u.Major, u.Majors = info.FullName, info.FullName
+	if i := strings.Index(info.FullName, "/"); i >= 0 {
+		u.Major = info.FullName[:i]
+		u.Majors = info.FullName[:strings.LastIndex(info.FullName, " ")+1] +
+			info.FullName[i+1:]
+	}
+	return u, nil
+}
+
+// ofUnit returns the word inserted between round millions and the given
+// unit name, elided before a vowel if the language requires it
+func (l language) ofUnit(unit string) string {
+	if l.elide && strings.ContainsAny(unit[:1], "aeiouy") {
+		return l.of[:len(l.of)-1] + "'" + unit
+	}
+
+	return l.of + " " + unit
+}
+
+// Words returns the Amount written out in words in the given Language, e.g.
+// "one hundred twenty-three dollars and forty-five cents". The value is
+// first rounded half away from zero to the decimal places of the currency.
+// The currencies without decimal places, such as XAU, are written with the
+// digits of their fraction, e.g. "twelve point three four". An error is
+// returned if the language is not supported, if the currency or its minor
+// unit has no name in the language, or if the value is too large
+func (a Amount) Words(lang Language) (string, error) {
+	l, ok := languages[lang]
+	if !ok {
+		return "", fmt.Errorf("The language %s is not supported.", lang)
+	}
+	names, err := l.unitNames(a.Currency.Code, lang)
+	if err != nil {
+		return "", err
+	}
+
+	places := a.places()
+	v := a.Value
+	if places >= 0 {
+		v = RoundHalfUp.Round(v, places)
+	}
+	neg := v.Cmp(decimal.New(0, 0)) < 0
+	if neg {
+		v = decimal.New(0, 0).Sub(v)
+	}
+	if v.Cmp(maxWords) >= 0 {
+		return "", fmt.Errorf("The amount %s is too large to be written in words.", v)
+	}
+
+	major := uint64(v.IntPart())
+	if places < 0 {
+		return l.fraction(major, v, names, neg), nil
+	}
+	minor := uint64(v.Sub(decimal.New(int64(major), 0)).Mul(decimal.New(1, places)).IntPart())
+	if minor > 0 && names.Minor == "" {
+		return "", fmt.Errorf("The minor unit of %s has no name in %s.", a.Currency.Code.format(), lang)
+	}
+
+	unit := names.major(l.singular(major))
+	if l.of != "" && major >= 1000000 && major%1000000 == 0 {
+		unit = l.ofUnit(unit)
+	}
+
+	words := []string{l.number(major, true), unit}
+	if minor > 0 {
+		words = append(words, l.and, l.number(minor, true), names.minor(l.singular(minor)))
+	}
+	if neg {
+		words = append([]string{l.minus}, words...)
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// fraction writes the positive value v, whose integer part is major, with
+// the digits of its fraction, for the currencies without minor unit
+func (l language) fraction(major uint64, v decimal.Decimal, names unitNames, neg bool) string {
+	var digits string
+	if s := v.String(); strings.IndexByte(s, '.') >= 0 {
+		digits = strings.TrimRight(s[strings.IndexByte(s, '.')+1:], "0")
+	}
+
+	words := []string{l.number(major, digits == "")}
+	if digits != "" {
+		words = append(words, l.point)
+		for _, d := range digits {
+			words = append(words, l.number(uint64(d-'0'), false))
+		}
+	}
+	words = append(words, names.major(digits == "" && l.singular(major)))
+	if neg {
+		words = append([]string{l.minus}, words...)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// scale is a power of ten named in words
+type scale struct {
+	value uint64
+	name  string
+}
+
+var (
+	englishOnes = []string{"zero", "one", "two", "three", "four", "five",
+		"six", "seven", "eight", "nine", "ten", "eleven", "twelve",
+		"thirteen", "fourteen", "fifteen", "sixteen", "seventeen",
+		"eighteen", "nineteen"}
+	englishTens = []string{"", "", "twenty", "thirty", "forty", "fifty",
+		"sixty", "seventy", "eighty", "ninety"}
+	englishScales = []scale{{1000000000000, "trillion"},
+		{1000000000, "billion"}, {1000000, "million"}, {1000, "thousand"}}
+	indianScales = []scale{{100000, "lakh"}, {1000, "thousand"}}
+)
+
+// englishWords writes n in words in English
+func englishWords(n uint64) string {
+	if n == 0 {
+		return englishOnes[0]
+	}
+
+	return strings.Join(englishGroups(n, englishScales), " ")
+}
+
+// indianWords writes n in words in English, grouping the digits in lakhs
+// and crores
+func indianWords(n uint64) string {
+	if n == 0 {
+		return englishOnes[0]
+	}
+
+	var words []string
+	if crores := n / 10000000; crores > 0 {
+		words = append(words, indianWords(crores), "crore")
+		n %= 10000000
+	}
+
+	return strings.Join(append(words, englishGroups(n, indianScales)...), " ")
+}
+
+// englishGroups writes n in words in English, using the given scales
+func englishGroups(n uint64, scales []scale) []string {
+	var words []string
+	for _, s := range scales {
+		if n >= s.value {
+			words = append(words, englishBelow1000(n/s.value), s.name)
+			n %= s.value
+		}
+	}
+	if n > 0 {
+		words = append(words, englishBelow1000(n))
+	}
+
+	return words
+}
+
+// englishBelow1000 writes n, which must be below 1000, in words in English
+func englishBelow1000(n uint64) string {
+	var words []string
+	if h := n / 100; h > 0 {
+		words = append(words, englishOnes[h], "hundred")
+		n %= 100
+	}
+
+	switch {
+	case n == 0:
+	case n < 20:
+		words = append(words, englishOnes[n])
+	case n%10 == 0:
+		words = append(words, englishTens[n/10])
+	default:
+		words = append(words, englishTens[n/10]+"-"+englishOnes[n%10])
+	}
+
+	return strings.Join(words, " ")
+}
+
+var (
+	frenchOnes = []string{"zéro", "un", "deux", "trois", "quatre", "cinq",
+		"six", "sept", "huit", "neuf", "dix", "onze", "douze", "treize",
+		"quatorze", "quinze", "seize"}
+	frenchTens = []string{"", "", "vingt", "trente", "quarante",
+		"cinquante", "soixante"}
+	frenchScales = []scale{{1000000000000, "billion"},
+		{1000000000, "milliard"}, {1000000, "million"}}
+)
+
+// frenchWords writes n in words in French
+func frenchWords(n uint64) string {
+	if n == 0 {
+		return frenchOnes[0]
+	}
+
+	var words []string
+	for _, s := range frenchScales {
+		if c := n / s.value; c > 0 {
+			name := s.name
+			if c > 1 {
+				name += "s"
+			}
+			words = append(words, frenchBelow1000(c, true), name)
+			n %= s.value
+		}
+	}
+
+	// mille is invariable, and is not preceded by un
+	if c := n / 1000; c > 0 {
+		if c > 1 {
+			words = append(words, frenchBelow1000(c, false))
+		}
+		words = append(words, "mille")
+		n %= 1000
+	}
+	if n > 0 {
+		words = append(words, frenchBelow1000(n, true))
+	}
+
+	return strings.Join(words, " ")
+}
+
+// frenchBelow1000 writes n, which must be below 1000, in words in French.
+// final is set when n is not followed by mille, so that vingt and cent
+// take the plural
+func frenchBelow1000(n uint64, final bool) string {
+	h, r := n/100, n%100
+	if h == 0 {
+		return frenchBelow100(r, final)
+	}
+
+	s := "cent"
+	if h > 1 {
+		s = frenchOnes[h] + " cent"
+	}
+	if r == 0 {
+		if h > 1 && final {
+			s += "s"
+		}
+		return s
+	}
+
+	return s + " " + frenchBelow100(r, final)
+}
+
+// frenchBelow100 writes n, which must be below 100, in words in French
+func frenchBelow100(n uint64, final bool) string {
+	if n < 17 {
+		return frenchOnes[n]
+	}
+	if n < 20 {
+		return "dix-" + frenchOnes[n-10]
+	}
+
+	t, u := n/10, n%10
+	switch {
+	case t < 7 && u == 0:
+		return frenchTens[t]
+	case t < 7 && u == 1:
+		return frenchTens[t] + " et un"
+	case t < 7:
+		return frenchTens[t] + "-" + frenchOnes[u]
+	case t == 7 && u == 1:
+		return "soixante et onze"
+	case t == 7:
+		return "soixante-" + frenchBelow100(10+u, final)
+	case t == 8 && u == 0 && final:
+		return "quatre-vingts"
+	case t == 8 && u == 0:
+		return "quatre-vingt"
+	case t == 8:
+		return "quatre-vingt-" + frenchOnes[u]
+	}
+
+	return "quatre-vingt-" + frenchBelow100(10+u, final)
+}
+
+var (
+	spanishOnes = []string{"cero", "uno", "dos", "tres", "cuatro", "cinco",
+		"seis", "siete", "ocho", "nueve", "diez", "once", "doce", "trece",
+		"catorce", "quince", "dieciséis", "diecisiete", "dieciocho",
+		"diecinueve", "veinte", "veintiuno", "veintidós", "veintitrés",
+		"veinticuatro", "veinticinco", "veintiséis", "veintisiete",
+		"veintiocho", "veintinueve"}
+	spanishTens = []string{"", "", "", "treinta", "cuarenta", "cincuenta",
+		"sesenta", "setenta", "ochenta", "noventa"}
+	spanishHundreds = []string{"", "ciento", "doscientos", "trescientos",
+		"cuatrocientos", "quinientos", "seiscientos", "setecientos",
+		"ochocientos", "novecientos"}
+)
+
+// spanishWords writes n in words in Spanish. If apocope is set, the final
+// uno is shortened to un, as n is followed by a noun
+func spanishWords(n uint64, apocope bool) string {
+	if n == 0 {
+		return spanishOnes[0]
+	}
+
+	var words []string
+	if c := n / 1000000000000; c > 0 {
+		if c == 1 {
+			words = append(words, "un billón")
+		} else {
+			words = append(words, spanishWords(c, true), "billones")
+		}
+		n %= 1000000000000
+	}
+	if c := n / 1000000; c > 0 {
+		if c == 1 {
+			words = append(words, "un millón")
+		} else {
+			words = append(words, spanishWords(c, true), "millones")
+		}
+		n %= 1000000
+	}
+	if c := n / 1000; c > 0 {
+		if c > 1 {
+			words = append(words, spanishApocope(spanishBelow1000(c)))
+		}
+		words = append(words, "mil")
+		n %= 1000
+	}
+	if n > 0 {
+		s := spanishBelow1000(n)
+		if apocope {
+			s = spanishApocope(s)
+		}
+		words = append(words, s)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// spanishBelow1000 writes n, which must be below 1000, in words in Spanish
+func spanishBelow1000(n uint64) string {
+	h, r := n/100, n%100
+	if h == 1 && r == 0 {
+		return "cien"
+	}
+
+	var words []string
+	if h > 0 {
+		words = append(words, spanishHundreds[h])
+	}
+	switch {
+	case r == 0:
+	case r < 30:
+		words = append(words, spanishOnes[r])
+	case r%10 == 0:
+		words = append(words, spanishTens[r/10])
+	default:
+		words = append(words, spanishTens[r/10], "y", spanishOnes[r%10])
+	}
+
+	return strings.Join(words, " ")
+}
+
+// spanishApocope shortens the final uno of s to un
+func spanishApocope(s string) string {
+	if strings.HasSuffix(s, "veintiuno") {
+		return strings.TrimSuffix(s, "veintiuno") + "veintiún"
+	}
+	if strings.HasSuffix(s, "uno") {
+		return strings.TrimSuffix(s, "o")
+	}
+
+	return s
+}
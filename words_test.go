@@ -0,0 +1,110 @@
+package trader
+
+import (
+	"testing"
+
+	"github.com/processout/decimal"
+)
+
+func getWordsTrader() Trader {
+	var currencies Currencies
+	for _, code := range []CurrencyCode{"USD", "EUR", "GBP", "INR", "JPY", "MXN", "KWD", "SEK", "XAU", "AED"} {
+		c, _ := NewCurrency(code, decimal.New(1, 0))
+		currencies = append(currencies, c)
+	}
+	trader, _ := New(currencies, "usd")
+	return trader
+}
+
+func TestAmount_Words(t *testing.T) {
+	trader := getWordsTrader()
+	tests := []struct {
+		value    string
+		code     CurrencyCode
+		lang     Language
+		expected string
+	}{
+		{"123.45", "usd", English, "one hundred twenty-three dollars and forty-five cents"},
+		{"1", "usd", English, "one dollar"},
+		{"0.01", "usd", English, "zero dollars and one cent"},
+		{"-2.5", "gbp", English, "minus two pounds and fifty pence"},
+		{"1000000", "usd", English, "one million dollars"},
+		{"2017.999", "usd", English, "two thousand eighteen dollars"},
+		{"1234", "jpy", English, "one thousand two hundred thirty-four yen"},
+		{"5.125", "kwd", English, "five dinars and one hundred twenty-five fils"},
+		{"100.50", "sek", English, "one hundred kronor and fifty öre"},
+		{"12.34", "xau", English, "twelve point three four Gold (one troy ounce)"},
+		{"-2.50", "xau", English, "minus two point five Gold (one troy ounce)"},
+		{"1", "xau", English, "one Gold (one troy ounce)"},
+		{"3", "aed", English, "three United Arab Emirates dirham"},
+		{"12345678.9", "inr", IndianEnglish,
+			"one crore twenty-three lakh forty-five thousand six hundred seventy-eight rupees and ninety paise"},
+		{"1500000000", "inr", IndianEnglish, "one hundred fifty crore rupees"},
+		{"123.45", "eur", French, "cent vingt-trois euros et quarante-cinq centimes"},
+		{"1", "eur", French, "un euro"},
+		{"0.5", "eur", French, "zéro euro et cinquante centimes"},
+		{"71", "eur", French, "soixante et onze euros"},
+		{"80", "eur", French, "quatre-vingts euros"},
+		{"80000", "eur", French, "quatre-vingt mille euros"},
+		{"91", "eur", French, "quatre-vingt-onze euros"},
+		{"200", "eur", French, "deux cents euros"},
+		{"201", "eur", French, "deux cent un euros"},
+		{"1000", "eur", French, "mille euros"},
+		{"2000000", "eur", French, "deux millions d'euros"},
+		{"123.45", "usd", Spanish, "ciento veintitrés dólares con cuarenta y cinco centavos"},
+		{"1", "usd", Spanish, "un dólar"},
+		{"21", "mxn", Spanish, "veintiún pesos"},
+		{"31.01", "mxn", Spanish, "treinta y un pesos con un centavo"},
+		{"100", "mxn", Spanish, "cien pesos"},
+		{"21000", "mxn", Spanish, "veintiún mil pesos"},
+		{"1000000", "mxn", Spanish, "un millón de pesos"},
+		{"3000000", "usd", French, "trois millions de dollars"},
+		{"1000000000", "mxn", Spanish, "mil millones de pesos"},
+	}
+
+	for _, test := range tests {
+		a, _ := trader.NewAmountFromString(test.value, test.code)
+		s, err := a.Words(test.lang)
+		if err != nil {
+			t.Errorf("%s %s: there shouldn't have been an error: %s", test.value, test.code, err)
+			continue
+		}
+		if s != test.expected {
+			t.Errorf("%s %s in %s should have been %q, got %q", test.value, test.code, test.lang, test.expected, s)
+		}
+	}
+}
+
+func TestAmount_WordsErrors(t *testing.T) {
+	trader := getWordsTrader()
+
+	a, _ := trader.NewAmountFromString("12", "usd")
+	if _, err := a.Words("de"); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	a, _ = trader.NewAmountFromString("1000000000000000", "usd")
+	if _, err := a.Words(English); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	// The names of the currencies are never mixed with another language
+	a, _ = trader.NewAmountFromString("100.50", "sek")
+	if _, err := a.Words(French); err == nil {
+		t.Error("There should have been an error")
+	}
+	if _, err := a.Words(Spanish); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	// The minor unit of the currencies named after their full name has no
+	// name
+	a, _ = trader.NewAmountFromString("3.50", "aed")
+	if _, err := a.Words(English); err == nil {
+		t.Error("There should have been an error")
+	}
+
+	if _, err := emptyAmount.Words(English); err == nil {
+		t.Error("There should have been an error")
+	}
+}
@@ -0,0 +1,102 @@
+package trader
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/processout/decimal"
+)
+
+const (
+	// maxISO20022Digits is the maximum number of digits of an ISO 20022
+	// amount
+	maxISO20022Digits = 18
+	// maxISO20022Fraction is the maximum number of fraction digits of an
+	// ISO 20022 amount, used for the currencies without decimal places
+	maxISO20022Fraction = 5
+)
+
+// MarshalXML implements xml.Marshaler. The Amount is encoded as an ISO
+// 20022 amount, e.g. <InstdAmt Ccy="EUR">123.45</InstdAmt>. An error is
+// returned if the Amount can't be represented in ISO 20022, see
+// ValidateISO20022
+func (a Amount) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := a.ValidateISO20022(); err != nil {
+		return err
+	}
+
+	start.Attr = append(start.Attr, xml.Attr{
+		Name:  xml.Name{Local: "Ccy"},
+		Value: a.Currency.Code.String(),
+	})
+	return e.EncodeElement(a.Value.StringFixed(a.displayPlaces()), start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler, and decodes an ISO 20022
+// amount. If the Amount already has a Trader, the currency must be
+// supported by it, like with UnmarshalText. An error is returned if the
+// amount is not valid in ISO 20022, see ValidateISO20022
+func (a *Amount) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var code CurrencyCode
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "Ccy" {
+			code = CurrencyCode(attr.Value)
+		}
+	}
+	if code == "" {
+		return fmt.Errorf("The amount %s has no Ccy attribute.", start.Name.Local)
+	}
+
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+
+	n := Amount{Trader: a.Trader}
+	if err := n.UnmarshalText([]byte(string(code) + " " + strings.TrimSpace(s))); err != nil {
+		return err
+	}
+	if err := n.ValidateISO20022(); err != nil {
+		return err
+	}
+
+	*a = n
+	return nil
+}
+
+// ValidateISO20022 returns an error if the Amount can't be represented in
+// an ISO 20022 message: if its currency is not part of ISO 4217, if its
+// value is negative, if it has more decimal places than its currency, or
+// more than 18 digits. The currencies without decimal places, such as XAU,
+// accept up to 5 fraction digits. Historic currency codes are not
+// supported, only the codes currently part of ISO 4217 are
+func (a Amount) ValidateISO20022() error {
+	if !a.Currency.Code.Verify() {
+		return UnknownCurrencyError{a.Currency.Code}
+	}
+	if a.Value.Cmp(decimal.New(0, 0)) < 0 {
+		return fmt.Errorf("The amount %s is negative.", a.Value)
+	}
+
+	// The trailing zeros are not significant
+	s := a.Value.String()
+	integer, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		integer, fraction = s[:i], s[i+1:]
+	}
+	integer = strings.TrimLeft(integer, "0")
+
+	places := a.places()
+	if places < 0 {
+		places = maxISO20022Fraction
+	}
+	if len(fraction) > int(places) {
+		return fmt.Errorf("The amount %s has more than %d decimal places.", a.Value, places)
+	}
+	if len(integer)+len(fraction) > maxISO20022Digits {
+		return fmt.Errorf("The amount %s has more than %d digits.", a.Value, maxISO20022Digits)
+	}
+
+	return nil
+}
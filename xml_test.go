@@ -0,0 +1,106 @@
+package trader
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/processout/decimal"
+)
+
+func TestAmount_MarshalXML(t *testing.T) {
+	trader := getWordsTrader()
+	type payment struct {
+		XMLName  xml.Name `xml:"CdtTrfTxInf"`
+		InstdAmt Amount
+	}
+
+	a, _ := trader.NewAmountFromString("123.450", "eur")
+	b, err := xml.Marshal(payment{InstdAmt: a})
+	if err != nil {
+		t.Errorf("There shouldn't have been an error: %s", err)
+	}
+	expected := `<CdtTrfTxInf><InstdAmt Ccy="EUR">123.45</InstdAmt></CdtTrfTxInf>`
+	if string(b) != expected {
+		t.Errorf("The amount was incorrectly marshaled: %s", b)
+	}
+
+	p := payment{InstdAmt: Amount{Trader: &trader}}
+	if err := xml.Unmarshal(b, &p); err != nil {
+		t.Errorf("There shouldn't have been an error: %s", err)
+	}
+	if !p.InstdAmt.Equal(a) || p.InstdAmt.Trader != a.Trader {
+		t.Error("The amount was incorrectly unmarshaled")
+	}
+
+	jpy, _ := trader.NewAmountFromString("1234", "jpy")
+	b, _ = xml.Marshal(payment{InstdAmt: jpy})
+	if string(b) != `<CdtTrfTxInf><InstdAmt Ccy="JPY">1234</InstdAmt></CdtTrfTxInf>` {
+		t.Errorf("The amount was incorrectly marshaled: %s", b)
+	}
+
+	xau, _ := trader.NewAmountFromString("12", "xau")
+	b, _ = xml.Marshal(payment{InstdAmt: xau})
+	if string(b) != `<CdtTrfTxInf><InstdAmt Ccy="XAU">12</InstdAmt></CdtTrfTxInf>` {
+		t.Errorf("The amount was incorrectly marshaled: %s", b)
+	}
+	xau, _ = trader.NewAmountFromString("1234.56789", "xau")
+	b, _ = xml.Marshal(payment{InstdAmt: xau})
+	if string(b) != `<CdtTrfTxInf><InstdAmt Ccy="XAU">1234.56789</InstdAmt></CdtTrfTxInf>` {
+		t.Errorf("The amount was incorrectly marshaled: %s", b)
+	}
+	xau, _ = trader.NewAmountFromString("1.234567", "xau")
+	if _, err := xml.Marshal(payment{InstdAmt: xau}); err == nil {
+		t.Error("The amount with more than 5 fraction digits should have been refused")
+	}
+
+	for _, v := range []string{"123.456", "-1", "12345678901234567.89"} {
+		a, _ := trader.NewAmountFromString(v, "eur")
+		if _, err := xml.Marshal(payment{InstdAmt: a}); err == nil {
+			t.Errorf("%s should have been refused", v)
+		}
+	}
+}
+
+func TestAmount_UnmarshalXML(t *testing.T) {
+	type payment struct {
+		InstdAmt Amount
+	}
+
+	var p payment
+	err := xml.Unmarshal([]byte(`<p><InstdAmt Ccy="SEK"> 1234567890123456.78 </InstdAmt></p>`), &p)
+	if err != nil {
+		t.Errorf("There shouldn't have been an error: %s", err)
+	}
+	expected, _ := decimal.NewFromString("1234567890123456.78")
+	if !p.InstdAmt.Currency.Is("sek") || !p.InstdAmt.Value.Equals(expected) {
+		t.Error("The amount was incorrectly unmarshaled")
+	}
+
+	invalid := []string{
+		`<p><InstdAmt>12.34</InstdAmt></p>`,
+		`<p><InstdAmt Ccy="LEL">12.34</InstdAmt></p>`,
+		`<p><InstdAmt Ccy="EUR">12.345</InstdAmt></p>`,
+		`<p><InstdAmt Ccy="JPY">12.5</InstdAmt></p>`,
+		`<p><InstdAmt Ccy="EUR">-12.34</InstdAmt></p>`,
+		`<p><InstdAmt Ccy="EUR">twelve</InstdAmt></p>`,
+		`<p><InstdAmt Ccy="EUR">12345678901234567.89</InstdAmt></p>`,
+		`<p><InstdAmt Ccy="XDR">1.000001</InstdAmt></p>`,
+	}
+	for _, s := range invalid {
+		var p payment
+		if err := xml.Unmarshal([]byte(s), &p); err == nil {
+			t.Errorf("%s should have been refused", s)
+		}
+	}
+
+	err = xml.Unmarshal([]byte(`<p><InstdAmt Ccy="XDR">1234.5</InstdAmt></p>`), &p)
+	if err != nil || !p.InstdAmt.Currency.Is("xdr") {
+		t.Errorf("The amount in XDR should have been accepted: %v", err)
+	}
+
+	trader := getTrader()
+	p = payment{InstdAmt: Amount{Trader: &trader}}
+	if err := xml.Unmarshal([]byte(`<p><InstdAmt Ccy="GBP">12.34</InstdAmt></p>`), &p); err == nil {
+		t.Error("The currency should have been refused by the trader")
+	}
+}